@@ -0,0 +1,45 @@
+/*
+Benchtable converts the output of the benchmarks into a markdown table, so that
+the libraries can be compared side by side.
+Usage:
+
+	go test -run '^$' -bench . ./... | go run ./cmd/benchtable
+*/
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const pkgPrefix = "github.com/veqryn/awesome-go-sql/cmd/"
+
+// BenchmarkDAO/SelectAllAccounts-8   	    1234	     56789 ns/op	    1234 B/op	      56 allocs/op
+var benchLine = regexp.MustCompile(`^Benchmark(\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op(?:\s+(\d+) B/op)?(?:\s+(\d+) allocs/op)?`)
+
+func main() {
+	fmt.Println("| Library | Benchmark | ns/op | B/op | allocs/op |")
+	fmt.Println("|---|---|--:|--:|--:|")
+
+	var library string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pkg, ok := strings.CutPrefix(line, "pkg: "); ok {
+			library = strings.TrimSuffix(strings.TrimPrefix(pkg, pkgPrefix), "/dao")
+			continue
+		}
+
+		match := benchLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		fmt.Printf("| %s | %s | %s | %s | %s |\n", library, match[1], match[2], match[3], match[4])
+	}
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,403 @@
+/*
+Build and run some queries using the bob library's psql query builder, and
+scan the rows with its sister library, github.com/stephenafamo/scan.
+Like squirrel and goqu, the builder only creates the SQL string and argument
+list, and its dialect packages know each database's own syntax, such as
+NULLS FIRST, ON CONFLICT, and RETURNING.
+The scan package maps the columns to the db struct tags, like scany, including
+the columns of a nested struct by their prefix, such as "order.id".
+It works with both database/sql and pgx, as scan/pgxscan adapts a pgx pool or
+transaction, so pgx is used natively here.
+Some disadvantages:
+  - Every column, table, and arg has to be wrapped in an expression, such as
+    psql.Quote("name") or psql.Arg(name), so the queries are more verbose than
+    with squirrel.
+  - Its In enumerates all values in the slice, like squirrel, goqu, jet, and
+    go-sqlbuilder. Unlike them, psql.Arg passes a slice as a single arg, so
+    postgres' = ANY($1) format can be written as
+    psql.Quote("name").EQ(psql.F("ANY", psql.Arg(names))), which is used here.
+  - bob can also generate models, with their own typed query mods, but its
+    bobgen-psql generator reads the schema from a running postgres, so they are
+    not used here.
+
+Some advantages:
+  - It has row value comparisons, with psql.Group and psql.ArgGroup, so the
+    cursor does not need any raw SQL.
+  - The scan package returns sql.ErrNoRows, even with pgx, so there is only one
+    error to check for.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/bob/dialect/psql/um"
+	"github.com/stephenafamo/scan"
+	"github.com/stephenafamo/scan/pgxscan"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// accountColumns are selected and returned by every account query
+var accountColumns = []any{
+	"id",
+	"name",
+	"email",
+	"active",
+	"fav_color",
+	"fav_numbers",
+	"properties",
+	"created_at",
+}
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := selectAccountByIDQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	account, err := pgxscan.One(ctx, d.db, scan.StructMapper[models.AccountIdeal](), sqlStr, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// selectAccountByIDQuery builds the SQL separately from running it, so that
+// building can be benchmarked on its own.
+func selectAccountByIDQuery(id uint64) (string, []any, error) {
+	query := psql.Select(
+		sm.Columns(accountColumns...),
+		sm.From("accounts"),
+		sm.Where(psql.Quote("id").EQ(psql.Arg(id))),
+	)
+	return query.Build()
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	sqlStr, args, err := selectAllAccountsQuery()
+	if err != nil {
+		return nil, err
+	}
+	return pgxscan.All(ctx, d.db, scan.StructMapper[models.AccountIdeal](), sqlStr, args...)
+}
+
+func selectAllAccountsQuery() (string, []any, error) {
+	query := psql.Select(
+		sm.Columns(accountColumns...),
+		sm.From("accounts"),
+		sm.OrderBy("id"),
+	)
+	return query.Build()
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, args)
+
+	accounts, err := pgxscan.All(ctx, d.db, scan.StructMapper[models.AccountIdeal](), sqlStr, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	query := psql.Select(
+		sm.Columns(accountColumns...),
+		sm.From("accounts"),
+	)
+
+	// psql.Arg passes each slice as a single array arg, for = ANY($1)
+	if len(filters.Names) > 0 {
+		query.Apply(sm.Where(psql.Quote("name").EQ(psql.F("ANY", psql.Arg(filters.Names)))))
+	}
+	if filters.Active != nil {
+		query.Apply(sm.Where(psql.Quote("active").EQ(psql.Arg(*filters.Active))))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		query.Apply(sm.Where(psql.Quote("fav_color").EQ(psql.F("ANY", psql.Arg(filters.FavColors)))))
+	}
+
+	// Continue after the cursor, if there is one
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		query.Apply(sm.Where(psql.Group(psql.Quote("created_at"), psql.Quote("id")).
+			GT(psql.ArgGroup(keyset.CreatedAt, keyset.ID))))
+	}
+
+	// The sort columns are whitelisted, and quoted as identifiers
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sort := range sorts {
+		orderBy := sm.OrderBy(psql.Quote(string(sort.Column)))
+		if sort.Desc {
+			orderBy = orderBy.Desc()
+		}
+		switch sort.Nulls {
+		case models.NullsFirst:
+			orderBy = orderBy.NullsFirst()
+		case models.NullsLast:
+			orderBy = orderBy.NullsLast()
+		}
+		query.Apply(orderBy)
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query.Apply(sm.Limit(psql.Arg(limit)))
+	}
+
+	return query.Build()
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := selectAccountWithOrdersQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// The scan package maps the columns of nested structs by their prefix,
+	// such as "order.id"
+	rows, err := pgxscan.All(ctx, d.db, scan.StructMapper[struct {
+		models.AccountIdeal
+		Order models.NullOrder `db:"order"`
+	}](), sqlStr, args...)
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].AccountIdeal
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func selectAccountWithOrdersQuery(id uint64) (string, []any, error) {
+	query := psql.Select(
+		sm.Columns(
+			psql.Quote("a", "id"),
+			psql.Quote("a", "name"),
+			psql.Quote("a", "email"),
+			psql.Quote("a", "active"),
+			psql.Quote("a", "fav_color"),
+			psql.Quote("a", "fav_numbers"),
+			psql.Quote("a", "properties"),
+			psql.Quote("a", "created_at"),
+			psql.Quote("o", "id").As("order.id"),
+			psql.Quote("o", "account_id").As("order.account_id"),
+			psql.Quote("o", "product").As("order.product"),
+			psql.Quote("o", "quantity").As("order.quantity"),
+			psql.Quote("o", "price_cents").As("order.price_cents"),
+			psql.Quote("o", "created_at").As("order.created_at"),
+		),
+		sm.From("accounts").As("a"),
+		sm.LeftJoin("orders").As("o").On(psql.Quote("o", "account_id").EQ(psql.Quote("a", "id"))),
+		sm.Where(psql.Quote("a", "id").EQ(psql.Arg(id))),
+		sm.OrderBy(psql.Quote("o", "id")),
+	)
+	return query.Build()
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	sqlStr, args, err := createAccountQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return pgxscan.One(ctx, d.db, scan.StructMapper[models.AccountIdeal](), sqlStr, args...)
+}
+
+func createAccountQuery(account models.AccountIdeal) (string, []any, error) {
+	query := psql.Insert(
+		im.Into("accounts",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at"),
+		im.Values(accountValues(account)...),
+		im.Returning(accountColumns...),
+	)
+	return query.Build()
+}
+
+// accountValues are the args of the account's inserted columns
+func accountValues(account models.AccountIdeal) []bob.Expression {
+	return []bob.Expression{
+		psql.Arg(account.Name),
+		psql.Arg(account.Email),
+		psql.Arg(account.Active),
+		psql.Arg(account.FavColor),
+		psql.Arg(account.FavNumbers),
+		psql.Arg(account.Properties),
+		psql.Arg(account.CreatedAt),
+	}
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sqlStr, args, err := updateAccountQuery(id, patch)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	models.LogQuery(sqlStr, args)
+
+	account, err := pgxscan.One(ctx, d.db, scan.StructMapper[models.AccountIdeal](), sqlStr, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func updateAccountQuery(id uint64, patch models.AccountPatch) (string, []any, error) {
+	query := psql.Update(
+		um.Table("accounts"),
+		um.Where(psql.Quote("id").EQ(psql.Arg(id))),
+		um.Returning(accountColumns...),
+	)
+
+	// Nicely add sets dynamically
+	if patch.Name != nil {
+		query.Apply(um.SetCol("name").ToArg(*patch.Name))
+	}
+	if patch.Email != nil {
+		query.Apply(um.SetCol("email").ToArg(*patch.Email))
+	}
+	if patch.Active != nil {
+		query.Apply(um.SetCol("active").ToArg(*patch.Active))
+	}
+	if patch.FavColor != nil {
+		query.Apply(um.SetCol("fav_color").ToArg(*patch.FavColor))
+	}
+	if patch.FavNumbers != nil {
+		query.Apply(um.SetCol("fav_numbers").ToArg(*patch.FavNumbers))
+	}
+	if patch.Properties != nil {
+		query.Apply(um.SetCol("properties").ToArg(*patch.Properties))
+	}
+
+	return query.Build()
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := upsertAccountByEmailQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	upserted, err := pgxscan.One(ctx, d.db, scan.StructMapper[struct {
+		models.AccountIdeal
+		Inserted bool `db:"inserted"`
+	}](), sqlStr, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted.AccountIdeal, upserted.Inserted, nil
+}
+
+func upsertAccountByEmailQuery(account models.AccountIdeal) (string, []any, error) {
+	query := psql.Insert(
+		im.Into("accounts",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at"),
+		im.Values(accountValues(account)...),
+		im.OnConflict("email").DoUpdate(
+			im.SetExcluded("name", "active", "fav_color", "fav_numbers", "properties"),
+		),
+		// xmax is only zero if the row was inserted, not updated
+		im.Returning(accountColumns...),
+		im.Returning(psql.Raw("(xmax = 0) AS inserted")),
+	)
+	return query.Build()
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// Each account uses 7 bind parameters, so chunk to stay under the limit
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		sqlStr, args, err := bulkInsertAccountsQuery(chunk)
+		if err != nil {
+			return total, err
+		}
+
+		tag, err := d.db.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
+func bulkInsertAccountsQuery(accounts []models.AccountIdeal) (string, []any, error) {
+	query := psql.Insert(
+		im.Into("accounts",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at"),
+	)
+
+	// Each call to Values adds another row
+	for _, account := range accounts {
+		query.Apply(im.Values(accountValues(account)...))
+	}
+	return query.Build()
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
@@ -0,0 +1,29 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/veqryn/awesome-go-sql/cmd/bob/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+	"github.com/veqryn/awesome-go-sql/txn"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(conformance.Pool(t)))
+}
+
+func TestTx(t *testing.T) {
+	db := conformance.Pool(t)
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return txn.WithPgxTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+			return fn(ctx, dao.New(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(conformance.Pool(b)))
+}
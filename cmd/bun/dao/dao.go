@@ -0,0 +1,220 @@
+/*
+Build and run some queries using the bun library.
+Bun combines a SQL Builder with a struct scanner, and also has an ORM for
+relations, which is not used here.
+It is built on database/sql, so it works with the database/sql version of pgx,
+but it does not work with PGX natively.
+Some disadvantages:
+  - Bun does its own interpolation, so the SQL sent to postgres has no bind
+    parameters at all. This is arguably a security concern, as I would much
+    rather trust Postgres to correctly interpolate parameters.
+  - It has its own scanner, which only maps columns using bun struct tags, so
+    models.AccountIdeal needs those, including the array option on fav_numbers,
+    without which the slice would be treated as json.
+  - The table name can only come from an embedded bun.BaseModel, which the
+    models package should not have to import, so the accountsTable wrapper is
+    only used to name the table.
+  - Scanning returns an error if the query selects a column the struct does not
+    have.
+  - Transactions have to be bun's own bun.Tx, begun from the bun.DB.
+
+Some advantages:
+  - Because it has its own scanner, it can scan straight into
+    models.AccountIdeal, even though it goes through database/sql.
+  - pgdialect.Array renders a slice as a single array literal, so the filters
+    can use postgres' = ANY format, instead of enumerating all values in the
+    slice.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// accountsTable names the table of models.AccountIdeal, and aliases it as "a"
+type accountsTable struct {
+	bun.BaseModel `bun:"table:accounts,alias:a"`
+	models.AccountIdeal
+}
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	var account models.AccountIdeal
+	err := d.db.NewSelect().
+		Model((*accountsTable)(nil)).
+		Where("a.id = ?", id).
+		Scan(ctx, &account)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	var accounts []models.AccountIdeal
+	err := d.db.NewSelect().
+		Model((*accountsTable)(nil)).
+		OrderExpr("a.id").
+		Scan(ctx, &accounts)
+	return accounts, err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := d.db.NewSelect().
+		Model((*accountsTable)(nil))
+
+	if len(filters.Names) > 0 {
+		query = query.Where("a.name = ANY(?)", pgdialect.Array(filters.Names))
+	}
+	if filters.Active != nil {
+		query = query.Where("a.active = ?", *filters.Active)
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		query = query.Where("a.fav_color = ANY(?)", pgdialect.Array(filters.FavColors))
+	}
+
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		query = query.Where("(a.created_at, a.id) > (?, ?)", keyset.CreatedAt, keyset.ID)
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	// Bun's Order only understands ASC and DESC, not NULLS FIRST or LAST
+	query = query.OrderExpr(models.OrderBySQL(sorts))
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	// The args are already interpolated into the SQL
+	models.LogQuery(query.String(), nil)
+
+	var accounts []models.AccountIdeal
+	if err = query.Scan(ctx, &accounts); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// Bun flattens embedded structs, and adds the embed prefix to the column
+	// names of the nested struct
+	var rows []struct {
+		models.AccountIdeal
+		Order models.NullOrder `bun:"embed:order_"`
+	}
+	err := d.db.NewSelect().
+		Model((*accountsTable)(nil)).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("o.id AS order_id").
+		ColumnExpr("o.account_id AS order_account_id").
+		ColumnExpr("o.product AS order_product").
+		ColumnExpr("o.quantity AS order_quantity").
+		ColumnExpr("o.price_cents AS order_price_cents").
+		ColumnExpr("o.created_at AS order_created_at").
+		Join("LEFT JOIN orders AS o ON o.account_id = a.id").
+		Where("a.id = ?", id).
+		OrderExpr("o.id").
+		Scan(ctx, &rows)
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].AccountIdeal
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	// The zero ID is inserted as DEFAULT, because of its autoincrement tag
+	created := accountsTable{AccountIdeal: account}
+	created.ID = 0
+	_, err := d.db.NewInsert().
+		Model(&created).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.AccountIdeal, nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	query := d.db.NewUpdate().
+		Model((*accountsTable)(nil))
+	if patch.Name != nil {
+		query = query.Set("name = ?", *patch.Name)
+	}
+	if patch.Email != nil {
+		query = query.Set("email = ?", *patch.Email)
+	}
+	if patch.Active != nil {
+		query = query.Set("active = ?", *patch.Active)
+	}
+	if patch.FavColor != nil {
+		query = query.Set("fav_color = ?", *patch.FavColor)
+	}
+	if patch.FavNumbers != nil {
+		// Outside of a model, a slice needs pgdialect.Array to not be encoded as json
+		query = query.Set("fav_numbers = ?", pgdialect.Array(*patch.FavNumbers))
+	}
+	if patch.Properties != nil {
+		query = query.Set("properties = ?", *patch.Properties)
+	}
+	query = query.
+		Where("a.id = ?", id).
+		Returning("*")
+
+	// The args are already interpolated into the SQL
+	models.LogQuery(query.String(), nil)
+
+	var account models.AccountIdeal
+	err := query.Scan(ctx, &account)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+type DAO struct {
+	db bun.IDB
+}
+
+// New returns a DAO, which implements models.AccountRepository.
+// It can run against either a *bun.DB or a bun.Tx.
+func New(db bun.IDB) DAO {
+	return DAO{db: db}
+}
+
+var _ models.AccountRepository = DAO{}
@@ -0,0 +1,30 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/veqryn/awesome-go-sql/cmd/bun/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(bun.NewDB(conformance.DB(t), pgdialect.New())))
+}
+
+func TestTx(t *testing.T) {
+	db := bun.NewDB(conformance.DB(t), pgdialect.New())
+	// Bun has its own transaction helper, that rolls back if fn returns an error
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return fn(ctx, dao.New(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(bun.NewDB(conformance.DB(b), pgdialect.New())))
+}
@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	// Building the SQL does not need a database, only the dialect
+	conn := &dbr.Connection{Dialect: dialect.PostgreSQL, EventReceiver: &dbr.NullEventReceiver{}}
+	d := New(conn.NewSession(nil))
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID: func(id uint64) (string, []any, error) {
+			return toSQL(d.selectAccountByIDQuery(id))
+		},
+		SelectAllAccounts: func() (string, []any, error) {
+			return toSQL(d.selectAllAccountsQuery())
+		},
+		SelectAllAccountsByFilter: func(filters models.Filters) (string, []any, error) {
+			query, err := d.selectAllAccountsByFilterQuery(filters)
+			if err != nil {
+				return "", nil, err
+			}
+			return toSQL(query)
+		},
+	})
+}
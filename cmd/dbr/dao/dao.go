@@ -0,0 +1,326 @@
+/*
+Build and run some queries using the gocraft/dbr library.
+DBR combines a SQL Builder with a struct loader, on top of database/sql.
+It only works with database/sql, so PGX can only be used through the
+pgx/v5/stdlib driver, and not natively.
+Some disadvantages:
+  - DBR does its own interpolation, so the SQL sent to postgres has no bind
+    parameters at all. This is arguably a security concern, as I would much
+    rather trust Postgres to correctly interpolate parameters.
+  - Because of its own interpolation, a slice arg is always enumerated as a
+    list, so it does not use postgres' =ANY($1) format for IN queries, an empty
+    slice is an error, and inserting a slice requires the models.Array[T]
+    wrapper.
+  - It calls Value on a nil *models.Color, which panics, so a nil color has to
+    be passed as an untyped nil.
+  - OrderAsc and OrderDesc have no NULLS FIRST or NULLS LAST, so the
+    whitelisted sorts are passed to OrderBy as raw SQL.
+  - Loading into a struct does not tell you if there were no rows, unless
+    loading with LoadOne, which has no version for INSERT or UPDATE.
+  - Because it goes through database/sql, it has to load into
+    models.AccountCompatible, which is then converted to models.AccountIdeal.
+  - Nested structs are flattened without any prefix, so the order columns of a
+    join need aliases that do not collide with the account columns.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	var account models.AccountCompatible
+	err := d.selectAccountByIDQuery(id).LoadOneContext(ctx, &account)
+	switch {
+	case errors.Is(err, dbr.ErrNotFound):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) selectAccountByIDQuery(id uint64) *dbr.SelectStmt {
+	query := d.db.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Where(dbr.Eq("id", id))
+
+	return query
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	var accounts []models.AccountCompatible
+	_, err := d.selectAllAccountsQuery().LoadContext(ctx, &accounts)
+	return models.CompatibleToIdeal(accounts), err
+}
+
+func (d DAO) selectAllAccountsQuery() *dbr.SelectStmt {
+	query := d.db.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		OrderAsc("id")
+
+	return query
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query, err := d.selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The args are interpolated into the SQL
+	sqlStr, _, err := toSQL(query)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, nil)
+
+	var accounts []models.AccountCompatible
+	if _, err = query.LoadContext(ctx, &accounts); err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(models.CompatibleToIdeal(accounts))
+	return ideals, next, nil
+}
+
+func (d DAO) selectAllAccountsByFilterQuery(filters models.Filters) (*dbr.SelectStmt, error) {
+	query := d.db.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts")
+
+	// Eq turns a slice into an IN list, which would be an error if empty
+	if len(filters.Names) > 0 {
+		query = query.Where(dbr.Eq("name", filters.Names))
+	}
+	if filters.Active != nil {
+		query = query.Where(dbr.Eq("active", *filters.Active))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, err
+		}
+		query = query.Where(dbr.Eq("fav_color", filters.FavColors))
+	}
+
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// DBR has no row value comparison, so it has to be an expression
+		query = query.Where(dbr.Expr("(created_at, id) > (?, ?)", keyset.CreatedAt, keyset.ID))
+	}
+
+	// DBR takes the ORDER BY as raw sql, which is only safe because the sort
+	// columns are whitelisted
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, err
+	}
+	for _, sort := range sorts {
+		query = query.OrderBy(sort.String())
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	return query, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// DBR flattens nested structs without any prefix, so the order columns
+	// need names that do not collide with the account columns
+	var accountOrders []struct {
+		models.AccountCompatible
+		OrderID         *uint64    `db:"order_id"`
+		OrderAccountID  *uint64    `db:"order_account_id"`
+		OrderProduct    *string    `db:"order_product"`
+		OrderQuantity   *int       `db:"order_quantity"`
+		OrderPriceCents *int64     `db:"order_price_cents"`
+		OrderCreatedAt  *time.Time `db:"order_created_at"`
+	}
+	_, err := d.db.Select(
+		"a.id",
+		"a.name",
+		"a.email",
+		"a.active",
+		"a.fav_color",
+		"a.fav_numbers",
+		"a.properties",
+		"a.created_at",
+		"o.id AS order_id",
+		"o.account_id AS order_account_id",
+		"o.product AS order_product",
+		"o.quantity AS order_quantity",
+		"o.price_cents AS order_price_cents",
+		"o.created_at AS order_created_at").
+		From("accounts a").
+		LeftJoin(dbr.I("orders").As("o"), "o.account_id = a.id").
+		Where(dbr.Eq("a.id", id)).
+		OrderAsc("o.id").
+		LoadContext(ctx, &accountOrders)
+	if err != nil || len(accountOrders) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := accountOrders[0].Ideal()
+	for _, row := range accountOrders {
+		order := models.NullOrder{
+			ID:         row.OrderID,
+			AccountID:  row.OrderAccountID,
+			Product:    row.OrderProduct,
+			Quantity:   row.OrderQuantity,
+			PriceCents: row.OrderPriceCents,
+			CreatedAt:  row.OrderCreatedAt,
+		}
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	var created models.AccountCompatible
+	err := d.db.InsertInto("accounts").
+		Columns(
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		Values(
+			account.Name,
+			account.Email,
+			account.Active,
+			colorArg(account.FavColor),
+			models.Array[int](account.FavNumbers), // A plain slice would be enumerated as a list
+			account.Properties,
+			account.CreatedAt).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		LoadContext(ctx, &created)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.Ideal(), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	query := d.db.Update("accounts")
+	if patch.Name != nil {
+		query = query.Set("name", *patch.Name)
+	}
+	if patch.Email != nil {
+		query = query.Set("email", *patch.Email)
+	}
+	if patch.Active != nil {
+		query = query.Set("active", *patch.Active)
+	}
+	if patch.FavColor != nil {
+		query = query.Set("fav_color", colorArg(*patch.FavColor))
+	}
+	if patch.FavNumbers != nil {
+		query = query.Set("fav_numbers", models.Array[int](*patch.FavNumbers))
+	}
+	if patch.Properties != nil {
+		query = query.Set("properties", *patch.Properties)
+	}
+	query = query.
+		Where(dbr.Eq("id", id)).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at")
+
+	// The args are interpolated into the SQL
+	sqlStr, _, err := toSQL(query)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	models.LogQuery(sqlStr, nil)
+
+	// Loading into a slice is the only way to tell if no row was updated
+	var accounts []models.AccountCompatible
+	if err = query.LoadContext(ctx, &accounts); err != nil || len(accounts) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+	return accounts[0].Ideal(), true, nil
+}
+
+type DAO struct {
+	db dbr.SessionRunner
+}
+
+// New returns a DAO, which implements models.AccountRepository.
+// It can run against either a *dbr.Session or a *dbr.Tx.
+func New(db dbr.SessionRunner) DAO {
+	return DAO{db: db}
+}
+
+var _ models.AccountRepository = DAO{}
+
+// toSQL interpolates the args into the SQL of the query, the same way that
+// DBR does before running it, so it never returns any args
+func toSQL(query dbr.Builder) (string, []any, error) {
+	sqlStr, err := dbr.InterpolateForDialect("?", []any{query}, dialect.PostgreSQL)
+	return sqlStr, nil, err
+}
+
+// colorArg returns the color, or an untyped nil, as DBR would otherwise call
+// Value on a nil *models.Color
+func colorArg(color *models.Color) any {
+	if color == nil {
+		return nil
+	}
+	return *color
+}
@@ -0,0 +1,42 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/veqryn/awesome-go-sql/cmd/dbr/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// open wraps the conformance database in a DBR session
+func open(tb testing.TB) *dbr.Session {
+	conn := &dbr.Connection{DB: conformance.DB(tb), Dialect: dialect.PostgreSQL, EventReceiver: &dbr.NullEventReceiver{}}
+	return conn.NewSession(nil)
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(open(t)))
+}
+
+func TestTx(t *testing.T) {
+	sess := open(t)
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		tx, err := sess.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		// Rolling back after a commit does nothing
+		defer tx.RollbackUnlessCommitted()
+		if err = fn(ctx, dao.New(tx)); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(open(b)))
+}
@@ -0,0 +1,278 @@
+/*
+Build and run some queries using the ent entity framework.
+Ent generates a typed client from a schema written in go, see
+internal/ent/schema, with a predicate function per column and operator, and an
+entity struct per table.
+It is built on database/sql, so it works with the database/sql version of pgx,
+but it does not work with PGX natively.
+Some disadvantages:
+  - The schema is written in go instead of SQL, and has to be kept in sync with
+    data/schema.sql by hand, as ent would rather own the schema through its own
+    migrations.
+  - The COLORS enum is generated as its own account.FavColor type, and its
+    postgres type has to be named with SchemaType, so it has to be converted to
+    and from models.Color.
+  - A plain slice field would be stored as json, so fav_numbers has to use a
+    custom type, models.Array[int], that implements sql.Scanner and
+    driver.Valuer.
+  - The In predicates enumerate every value, such as fav_color IN ($1, $2),
+    instead of using postgres' = ANY format, so each length of filter is a
+    different query.
+  - Row-value comparisons are not supported, so the cursor has to be written
+    out as (created_at > $1 OR (created_at = $1 AND id > $2)).
+  - The orders edge is eager loaded with a second query, instead of a join.
+  - Updating a single entity runs an UPDATE, and then a SELECT of the updated
+    row, in a transaction that ent begins itself, instead of using RETURNING.
+  - It can not show the SQL it built without running it, other than through
+    its debug driver, which logs each query as a single string.
+
+Some advantages:
+  - Everything is type checked, including the columns, the order by, and the
+    values of the enum.
+  - NULL is set with a Clear method per nullable column, such as ClearFavColor,
+    instead of passing a nil.
+*/
+package dao
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/veqryn/awesome-go-sql/cmd/ent/internal/ent"
+	"github.com/veqryn/awesome-go-sql/cmd/ent/internal/ent/account"
+	"github.com/veqryn/awesome-go-sql/cmd/ent/internal/ent/order"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	entAccount, err := d.client.Account.Get(ctx, id)
+	switch {
+	case ent.IsNotFound(err):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return AccountToIdeal(entAccount), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	entAccounts, err := d.client.Account.Query().
+		Order(account.ByID()).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return AccountsToIdeal(entAccounts), nil
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := d.client.Account.Query()
+
+	if len(filters.Names) > 0 {
+		query = query.Where(account.NameIn(filters.Names...))
+	}
+	if filters.Active != nil {
+		query = query.Where(account.Active(*filters.Active))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		favColors := make([]account.FavColor, 0, len(filters.FavColors))
+		for _, color := range filters.FavColors {
+			favColors = append(favColors, account.FavColor(color))
+		}
+		query = query.Where(account.FavColorIn(favColors...))
+	}
+
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		query = query.Where(account.Or(
+			account.CreatedAtGT(keyset.CreatedAt),
+			account.And(account.CreatedAt(keyset.CreatedAt), account.IDGT(keyset.ID)),
+		))
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	// The sort columns are whitelisted, and have the same names as the fields
+	for _, sort := range sorts {
+		var opts []sql.OrderTermOption
+		if sort.Desc {
+			opts = append(opts, sql.OrderDesc())
+		}
+		switch sort.Nulls {
+		case models.NullsFirst:
+			opts = append(opts, sql.OrderNullsFirst())
+		case models.NullsLast:
+			opts = append(opts, sql.OrderNullsLast())
+		}
+		query = query.Order(sql.OrderByField(string(sort.Column), opts...).ToFunc())
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	entAccounts, err := query.All(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(AccountsToIdeal(entAccounts))
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// The orders are loaded by a second query, once the account has been found
+	entAccount, err := d.client.Account.Query().
+		Where(account.ID(id)).
+		WithOrders(func(query *ent.OrderQuery) {
+			query.Order(order.ByID())
+		}).
+		Only(ctx)
+	switch {
+	case ent.IsNotFound(err):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	}
+
+	ideal := AccountToIdeal(entAccount)
+	for _, entOrder := range entAccount.Edges.Orders {
+		ideal.Orders = append(ideal.Orders, models.Order{
+			ID:         entOrder.ID,
+			AccountID:  entOrder.AccountID,
+			Product:    entOrder.Product,
+			Quantity:   entOrder.Quantity,
+			PriceCents: entOrder.PriceCents,
+			CreatedAt:  entOrder.CreatedAt,
+		})
+	}
+	return ideal, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	create := d.client.Account.Create().
+		SetName(account.Name).
+		SetEmail(account.Email).
+		SetActive(account.Active).
+		SetCreatedAt(account.CreatedAt)
+	if account.FavColor != nil {
+		create = create.SetFavColor(favColor(*account.FavColor))
+	}
+	if account.FavNumbers != nil {
+		create = create.SetFavNumbers(account.FavNumbers)
+	}
+	// The zero JSONB is left out, so that it is NULL instead of a json null
+	if account.Properties.Valid {
+		create = create.SetProperties(&account.Properties.V)
+	}
+
+	entAccount, err := create.Save(ctx)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return AccountToIdeal(entAccount), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	update := d.client.Account.UpdateOneID(id).
+		SetNillableName(patch.Name).
+		SetNillableEmail(patch.Email).
+		SetNillableActive(patch.Active)
+	if patch.FavColor != nil {
+		if *patch.FavColor == nil {
+			update = update.ClearFavColor()
+		} else {
+			update = update.SetFavColor(favColor(**patch.FavColor))
+		}
+	}
+	if patch.FavNumbers != nil {
+		if *patch.FavNumbers == nil {
+			update = update.ClearFavNumbers()
+		} else {
+			update = update.SetFavNumbers(*patch.FavNumbers)
+		}
+	}
+	if patch.Properties != nil {
+		if !patch.Properties.Valid {
+			update = update.ClearProperties()
+		} else {
+			update = update.SetProperties(&patch.Properties.V)
+		}
+	}
+
+	entAccount, err := update.Save(ctx)
+	switch {
+	case ent.IsNotFound(err):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return AccountToIdeal(entAccount), true, nil
+	}
+}
+
+type DAO struct {
+	client *ent.Client
+}
+
+// New returns a DAO, which implements models.AccountRepository.
+// It can run against either an *ent.Client, or the client of an *ent.Tx.
+func New(client *ent.Client) DAO {
+	return DAO{client: client}
+}
+
+var _ models.AccountRepository = DAO{}
+
+// AccountToIdeal converts the generated entity to an AccountIdeal
+func AccountToIdeal(a *ent.Account) models.AccountIdeal {
+	var color *models.Color
+	if a.FavColor != nil {
+		c := models.Color(*a.FavColor)
+		color = &c
+	}
+
+	// A NULL jsonb column leaves the generated pointer nil
+	var properties models.JSONB[models.AccountProperties]
+	if a.Properties != nil {
+		properties = models.NewJSONB(*a.Properties)
+	}
+
+	return models.AccountIdeal{
+		ID:         a.ID,
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   color,
+		FavNumbers: a.FavNumbers,
+		Properties: properties,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// AccountsToIdeal converts the generated entities to AccountIdeals
+func AccountsToIdeal(accounts []*ent.Account) []models.AccountIdeal {
+	if accounts == nil {
+		return nil
+	}
+	ideals := make([]models.AccountIdeal, 0, len(accounts))
+	for _, a := range accounts {
+		ideals = append(ideals, AccountToIdeal(a))
+	}
+	return ideals
+}
+
+// favColor converts the color to the enum type generated by ent
+func favColor(color models.Color) account.FavColor {
+	return account.FavColor(color)
+}
@@ -0,0 +1,43 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/veqryn/awesome-go-sql/cmd/ent/dao"
+	"github.com/veqryn/awesome-go-sql/cmd/ent/internal/ent"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// open wraps the conformance database in an ent client
+func open(tb testing.TB) *ent.Client {
+	return ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, conformance.DB(tb))))
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(open(t)))
+}
+
+func TestTx(t *testing.T) {
+	client := open(t)
+	// The client of an ent.Tx runs everything in the transaction, including the
+	// transactions that ent begins itself, such as for UpdateOne
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		tx, err := client.Tx(ctx)
+		if err != nil {
+			return err
+		}
+		if err = fn(ctx, dao.New(tx.Client())); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(open(b)))
+}
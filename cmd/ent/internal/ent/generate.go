@@ -0,0 +1,9 @@
+package ent
+
+// Run with go generate -x ./...
+// This generates the client and models in this package from the schema
+// package.
+// The ent v0.13.1 command loads the schema with an old golang.org/x/tools,
+// which fails on newer go versions, so it may have to be built with a newer
+// golang.org/x/tools, and run as `ent generate ./schema` instead.
+//go:generate go run -mod=mod entgo.io/ent/cmd/ent generate ./schema
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// Account mirrors the accounts table in data/schema.sql
+type Account struct {
+	ent.Schema
+}
+
+func (Account) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "accounts"},
+	}
+}
+
+func (Account) Fields() []ent.Field {
+	return []ent.Field{
+		field.Uint64("id"),
+		field.String("name").
+			MaxLen(50),
+		field.String("email").
+			MaxLen(50).
+			Unique(),
+		field.Bool("active"),
+		// ent generates its own type for the enum, with its own validation
+		field.Enum("fav_color").
+			Values("red", "green", "blue").
+			SchemaType(map[string]string{dialect.Postgres: "colors"}).
+			Optional().
+			Nillable(),
+		// ent stores field.Ints as json, so the array needs a sql.Scanner and
+		// driver.Valuer instead
+		field.Other("fav_numbers", models.Array[int]{}).
+			SchemaType(map[string]string{dialect.Postgres: "integer[]"}).
+			Optional(),
+		// A nil pointer is NULL, as ent only unmarshals the json if it is not NULL
+		field.JSON("properties", &models.AccountProperties{}).
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}).
+			Optional(),
+		field.Time("created_at").
+			Immutable(),
+	}
+}
+
+func (Account) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("orders", Order.Type),
+	}
+}
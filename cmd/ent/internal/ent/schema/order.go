@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Order mirrors the orders table in data/schema.sql
+type Order struct {
+	ent.Schema
+}
+
+func (Order) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "orders"},
+	}
+}
+
+func (Order) Fields() []ent.Field {
+	return []ent.Field{
+		field.Uint64("id"),
+		field.Uint64("account_id"),
+		field.String("product").
+			MaxLen(50),
+		field.Int("quantity"),
+		field.Int64("price_cents"),
+		field.Time("created_at").
+			Immutable(),
+	}
+}
+
+func (Order) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("account", Account.Type).
+			Ref("orders").
+			Field("account_id").
+			Unique().
+			Required(),
+	}
+}
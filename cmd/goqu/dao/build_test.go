@@ -0,0 +1,20 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	d := New(nil) // Building the SQL does not need a database
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID: func(id uint64) (string, []any, error) {
+			return d.selectAccountByIDQuery(id).ToSQL()
+		},
+		SelectAllAccounts: func() (string, []any, error) {
+			return d.selectAllAccountsQuery().ToSQL()
+		},
+		SelectAllAccountsByFilter: d.selectAllAccountsByFilterQuery,
+	})
+}
@@ -0,0 +1,379 @@
+/*
+Build and run some queries using the goqu library.
+GOQU combines both a SQL Builder with a struct scanner.
+Because of this, the boilerplate code is cut to an absolute minimum.
+Some disadvantages:
+  - GOQU does its own interpolation. This is arguably a security concern, as I
+    would much rather trust Postgres to correctly interpolate parameters.
+  - A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+    for IN queries using a slice, and instead enumerates all values in the slice.
+  - The struct scanning only works if you pass the database/sql package's DB
+    object in, which means it only works with database/sql and does not work
+    with PGX.
+    You could choose to only use the builder, and use a different scanning
+    library, if you wanted PGX.
+  - Because it goes through database/sql, it has to scan into
+    models.AccountCompatible, which is then converted to models.AccountIdeal.
+  - Because of its own interpolation, inserting a slice requires the
+    models.Array[T] wrapper, and json has to be a string, as []byte would be
+    enumerated as a list, which models.JSONB[T] takes care of.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	var account models.AccountCompatible
+	ok, err := d.selectAccountByIDQuery(id).ScanStructContext(ctx, &account)
+	return account.Ideal(), ok, err
+}
+
+func (d DAO) selectAccountByIDQuery(id uint64) *goqu.SelectDataset {
+	query := d.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Where(goqu.Ex{"id": id})
+		//Prepared(true). // Doesn't work for postgres
+
+	return query
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	var accounts []models.AccountCompatible
+	err := d.selectAllAccountsQuery().ScanStructsContext(ctx, &accounts)
+	return models.CompatibleToIdeal(accounts), err
+}
+
+func (d DAO) selectAllAccountsQuery() *goqu.SelectDataset {
+	query := d.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Order(goqu.C("id").Asc())
+		// Prepared(true). // Doesn't work for postgres
+
+	return query
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := d.selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+
+	models.LogQuery(sqlStr, args)
+
+	var accounts []models.AccountCompatible
+	if err = d.ScanStructsContext(ctx, &accounts, sqlStr, args...); err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(models.CompatibleToIdeal(accounts))
+	return ideals, next, nil
+}
+
+func (d DAO) selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	query := d.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts")
+		// .Prepared(true) // Doesn't work for postgres
+
+	if len(filters.Names) > 0 {
+		query = query.Where(goqu.Ex{"name": filters.Names})
+	}
+	if filters.Active != nil {
+		query = query.Where(goqu.Ex{"active": *filters.Active})
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		query = query.Where(goqu.Ex{"fav_color": filters.FavColors})
+	}
+
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		// GOQU has no row value comparison, so it has to be a literal
+		query = query.Where(goqu.L("(created_at, id) > (?, ?)", keyset.CreatedAt, keyset.ID))
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sort := range sorts {
+		query = query.OrderAppend(orderedExpression(sort))
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(uint(limit))
+	}
+
+	return query.ToSQL()
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// GOQU maps the columns of nested structs by their prefix, such as "order.id"
+	var rows []struct {
+		models.AccountCompatible
+		Order models.NullOrder `db:"order"`
+	}
+	err := d.Select(
+		goqu.I("a.id"),
+		goqu.I("a.name"),
+		goqu.I("a.email"),
+		goqu.I("a.active"),
+		goqu.I("a.fav_color"),
+		goqu.I("a.fav_numbers"),
+		goqu.I("a.properties"),
+		goqu.I("a.created_at"),
+		goqu.I("o.id").As(goqu.C("order.id")),
+		goqu.I("o.account_id").As(goqu.C("order.account_id")),
+		goqu.I("o.product").As(goqu.C("order.product")),
+		goqu.I("o.quantity").As(goqu.C("order.quantity")),
+		goqu.I("o.price_cents").As(goqu.C("order.price_cents")),
+		goqu.I("o.created_at").As(goqu.C("order.created_at"))).
+		From(goqu.T("accounts").As("a")).
+		LeftJoin(goqu.T("orders").As("o"), goqu.On(goqu.I("o.account_id").Eq(goqu.I("a.id")))).
+		Where(goqu.I("a.id").Eq(id)).
+		Order(goqu.I("o.id").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].Ideal()
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	var created models.AccountCompatible
+	_, err := d.Insert("accounts").
+		Rows(goqu.Record{
+			"name":        account.Name,
+			"email":       account.Email,
+			"active":      account.Active,
+			"fav_color":   account.FavColor,
+			"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+			"properties":  account.Properties,
+			"created_at":  account.CreatedAt,
+		}).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		Executor().
+		ScanStructContext(ctx, &created)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.Ideal(), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sets := goqu.Record{}
+	if patch.Name != nil {
+		sets["name"] = *patch.Name
+	}
+	if patch.Email != nil {
+		sets["email"] = *patch.Email
+	}
+	if patch.Active != nil {
+		sets["active"] = *patch.Active
+	}
+	if patch.FavColor != nil {
+		sets["fav_color"] = *patch.FavColor
+	}
+	if patch.FavNumbers != nil {
+		sets["fav_numbers"] = models.Array[int](*patch.FavNumbers) // GOQU would enumerate a slice as an IN list
+	}
+	if patch.Properties != nil {
+		sets["properties"] = *patch.Properties
+	}
+
+	sqlStr, args, err := d.Update("accounts").
+		Set(sets).
+		Where(goqu.Ex{"id": id}).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		ToSQL()
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	models.LogQuery(sqlStr, args)
+
+	var account models.AccountCompatible
+	ok, err := d.ScanStructContext(ctx, &account, sqlStr, args...)
+	return account.Ideal(), ok, err
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	var upserted struct {
+		models.AccountCompatible
+		Inserted bool `db:"inserted"`
+	}
+	_, err := d.Insert("accounts").
+		Rows(goqu.Record{
+			"name":        account.Name,
+			"email":       account.Email,
+			"active":      account.Active,
+			"fav_color":   account.FavColor,
+			"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+			"properties":  account.Properties,
+			"created_at":  account.CreatedAt,
+		}).
+		OnConflict(goqu.DoUpdate("email", goqu.Record{
+			"name":        goqu.I("excluded.name"),
+			"active":      goqu.I("excluded.active"),
+			"fav_color":   goqu.I("excluded.fav_color"),
+			"fav_numbers": goqu.I("excluded.fav_numbers"),
+			"properties":  goqu.I("excluded.properties"),
+		})).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at",
+			goqu.L("(xmax = 0)").As("inserted")). // xmax is only zero if the row was inserted, not updated
+		Executor().
+		ScanStructContext(ctx, &upserted)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted.Ideal(), upserted.Inserted, nil
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// GOQU interpolates the values instead of using bind parameters, but the
+	// chunks are kept the same size as the other libraries' anyway.
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		rows := make([]any, 0, len(chunk))
+		for _, account := range chunk {
+			rows = append(rows, goqu.Record{
+				"name":        account.Name,
+				"email":       account.Email,
+				"active":      account.Active,
+				"fav_color":   account.FavColor,
+				"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+				"properties":  account.Properties,
+				"created_at":  account.CreatedAt,
+			})
+		}
+
+		result, err := d.Insert("accounts").Rows(rows...).Executor().ExecContext(ctx)
+		if err != nil {
+			return total, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// orderedExpression converts a sort validated by models.Filters.OrderBy into a
+// GOQU ordered column
+func orderedExpression(sort models.Sort) exp.OrderedExpression {
+	column := goqu.C(string(sort.Column))
+	ordered := column.Asc()
+	if sort.Desc {
+		ordered = column.Desc()
+	}
+	switch sort.Nulls {
+	case models.NullsFirst:
+		return ordered.NullsFirst()
+	case models.NullsLast:
+		return ordered.NullsLast()
+	default:
+		return ordered
+	}
+}
+
+type DAO struct {
+	database // Wrap the db connection or transaction
+}
+
+// database is implemented by both *goqu.Database and *goqu.TxDatabase
+type database interface {
+	Select(cols ...any) *goqu.SelectDataset
+	Insert(table any) *goqu.InsertDataset
+	Update(table any) *goqu.UpdateDataset
+	ScanStructContext(ctx context.Context, i any, query string, args ...any) (bool, error)
+	ScanStructsContext(ctx context.Context, i any, query string, args ...any) error
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter
+func New(db *sql.DB) DAO {
+	return DAO{database: goqu.New("postgres", db)}
+}
+
+// NewTx returns a DAO that runs inside the transaction.
+// GOQU has separate types for a transaction, so it needs its own constructor.
+func NewTx(tx *sql.Tx) DAO {
+	return DAO{database: goqu.NewTx("postgres", tx)}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
@@ -0,0 +1,29 @@
+package dao_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/cmd/goqu/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+	"github.com/veqryn/awesome-go-sql/txn"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(conformance.DB(t)))
+}
+
+func TestTx(t *testing.T) {
+	db := conformance.DB(t)
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return txn.WithTx(ctx, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+			return fn(ctx, dao.NewTx(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(conformance.DB(b)))
+}
@@ -0,0 +1,16 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	d := New(nil) // Building the SQL does not need a database
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID:         d.selectAccountByIDQuery,
+		SelectAllAccounts:         d.selectAllAccountsQuery,
+		SelectAllAccountsByFilter: d.selectAllAccountsByFilterQuery,
+	})
+}
@@ -0,0 +1,545 @@
+/*
+Build and run some queries using the goqu library.
+GOQU combines both a SQL Builder with a struct scanner.
+Because of this, the boilerplate code is cut to an absolute minimum.
+Some disadvantages:
+  - GOQU does its own interpolation. This is arguably a security concern, as I
+    would much rather trust Postgres to correctly interpolate parameters.
+  - A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+    for IN queries using a slice, and instead enumerates all values in the slice.
+  - The struct scanning only works if you pass the database/sql package's DB
+    object in, which means it only works with database/sql and does not work
+    with PGX.
+    You could choose to only use the builder, and use a different scanning
+    library, if you wanted PGX.
+  - Because of its own interpolation, inserting a slice requires the
+    models.Array[T] wrapper, and json has to be a string, as []byte would be
+    enumerated as a list, which models.JSONB[T] takes care of.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := d.selectAccountByIDQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	var account models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// selectAccountByIDQuery builds the SQL separately from running it, so that
+// building can be benchmarked on its own.
+func (d DAO) selectAccountByIDQuery(id uint64) (string, []any, error) {
+	query := d.builder.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Where(goqu.Ex{"id": id})
+	//Prepared(true). // Doesn't work for postgres
+
+	return query.ToSQL()
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	sqlStr, args, err := d.selectAllAccountsQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (d DAO) selectAllAccountsQuery() (string, []any, error) {
+	query := d.builder.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Order(goqu.C("id").Asc())
+		//Prepared(true). // Doesn't work for postgres
+
+	return query.ToSQL()
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := d.selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, args)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, "", scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	query := d.builder.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts")
+		//Prepared(true). // Doesn't work for postgres
+
+	// Nicely add filters dynamically
+	if len(filters.Names) > 0 {
+		query = query.Where(goqu.Ex{"name": filters.Names})
+	}
+	if filters.Active != nil {
+		query = query.Where(goqu.Ex{"active": *filters.Active})
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		query = query.Where(goqu.Ex{"fav_color": filters.FavColors})
+	}
+
+	// Continue after the cursor, if there is one
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		// GOQU has no row value comparison, so it has to be a literal
+		query = query.Where(goqu.L("(created_at, id) > (?, ?)", keyset.CreatedAt, keyset.ID))
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sort := range sorts {
+		query = query.OrderAppend(orderedExpression(sort))
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(uint(limit))
+	}
+
+	return query.ToSQL()
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := d.selectAccountWithOrdersQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	defer rows.Close()
+
+	// Every row repeats the account columns, with the columns of one order,
+	// so the rows have to be folded into a single account by hand
+	var account models.AccountIdeal
+	var found bool
+	for rows.Next() {
+		var order models.NullOrder
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&order.ID, // The LEFT JOIN makes every order column nullable
+			&order.AccountID,
+			&order.Product,
+			&order.Quantity,
+			&order.PriceCents,
+			&order.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return models.AccountIdeal{}, false, scanErr
+		}
+		found = true
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return account, found, nil
+}
+
+func (d DAO) selectAccountWithOrdersQuery(id uint64) (string, []any, error) {
+	query := d.builder.Select(
+		goqu.I("a.id"),
+		goqu.I("a.name"),
+		goqu.I("a.email"),
+		goqu.I("a.active"),
+		goqu.I("a.fav_color"),
+		goqu.I("a.fav_numbers"),
+		goqu.I("a.properties"),
+		goqu.I("a.created_at"),
+		goqu.I("o.id"),
+		goqu.I("o.account_id"),
+		goqu.I("o.product"),
+		goqu.I("o.quantity"),
+		goqu.I("o.price_cents"),
+		goqu.I("o.created_at")).
+		From(goqu.T("accounts").As("a")).
+		LeftJoin(goqu.T("orders").As("o"), goqu.On(goqu.I("o.account_id").Eq(goqu.I("a.id")))).
+		Where(goqu.I("a.id").Eq(id)).
+		Order(goqu.I("o.id").Asc())
+
+	return query.ToSQL()
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	sqlStr, args, err := d.createAccountQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+
+	var created models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.Active,
+		&created.FavColor,
+		&created.FavNumbers,
+		&created.Properties,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func (d DAO) createAccountQuery(account models.AccountIdeal) (string, []any, error) {
+	query := d.builder.Insert("accounts").
+		Rows(goqu.Record{
+			"name":        account.Name,
+			"email":       account.Email,
+			"active":      account.Active,
+			"fav_color":   account.FavColor,
+			"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+			"properties":  account.Properties,
+			"created_at":  account.CreatedAt,
+		}).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at")
+
+	return query.ToSQL()
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sqlStr, args, err := d.updateAccountQuery(id, patch)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	models.LogQuery(sqlStr, args)
+
+	var account models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) updateAccountQuery(id uint64, patch models.AccountPatch) (string, []any, error) {
+	// Nicely add sets dynamically
+	sets := goqu.Record{}
+	if patch.Name != nil {
+		sets["name"] = *patch.Name
+	}
+	if patch.Email != nil {
+		sets["email"] = *patch.Email
+	}
+	if patch.Active != nil {
+		sets["active"] = *patch.Active
+	}
+	if patch.FavColor != nil {
+		sets["fav_color"] = *patch.FavColor
+	}
+	if patch.FavNumbers != nil {
+		sets["fav_numbers"] = models.Array[int](*patch.FavNumbers) // GOQU would enumerate a slice as an IN list
+	}
+	if patch.Properties != nil {
+		sets["properties"] = *patch.Properties
+	}
+
+	query := d.builder.Update("accounts").
+		Set(sets).
+		Where(goqu.Ex{"id": id}).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at")
+
+	return query.ToSQL()
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := d.upsertAccountByEmailQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	var upserted models.AccountIdeal
+	var inserted bool
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&upserted.ID,
+		&upserted.Name,
+		&upserted.Email,
+		&upserted.Active,
+		&upserted.FavColor,
+		&upserted.FavNumbers,
+		&upserted.Properties,
+		&upserted.CreatedAt,
+		&inserted,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted, inserted, nil
+}
+
+func (d DAO) upsertAccountByEmailQuery(account models.AccountIdeal) (string, []any, error) {
+	query := d.builder.Insert("accounts").
+		Rows(goqu.Record{
+			"name":        account.Name,
+			"email":       account.Email,
+			"active":      account.Active,
+			"fav_color":   account.FavColor,
+			"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+			"properties":  account.Properties,
+			"created_at":  account.CreatedAt,
+		}).
+		OnConflict(goqu.DoUpdate("email", goqu.Record{
+			"name":        goqu.I("excluded.name"),
+			"active":      goqu.I("excluded.active"),
+			"fav_color":   goqu.I("excluded.fav_color"),
+			"fav_numbers": goqu.I("excluded.fav_numbers"),
+			"properties":  goqu.I("excluded.properties"),
+		})).
+		Returning(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at",
+			goqu.L("(xmax = 0)").As("inserted")) // xmax is only zero if the row was inserted, not updated
+
+	return query.ToSQL()
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		sqlStr, args, err := d.bulkInsertAccountsQuery(chunk)
+		if err != nil {
+			return total, err
+		}
+
+		tag, err := d.db.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
+// bulkInsertAccountsQuery builds a multi-row INSERT ... VALUES.
+// GOQU interpolates the values instead of using bind parameters, but the
+// chunks are kept the same size as the other libraries' anyway.
+func (d DAO) bulkInsertAccountsQuery(accounts []models.AccountIdeal) (string, []any, error) {
+	rows := make([]any, 0, len(accounts))
+	for _, account := range accounts {
+		rows = append(rows, goqu.Record{
+			"name":        account.Name,
+			"email":       account.Email,
+			"active":      account.Active,
+			"fav_color":   account.FavColor,
+			"fav_numbers": models.Array[int](account.FavNumbers), // GOQU would enumerate a slice as an IN list
+			"properties":  account.Properties,
+			"created_at":  account.CreatedAt,
+		})
+	}
+
+	return d.builder.Insert("accounts").Rows(rows...).ToSQL()
+}
+
+// orderedExpression converts a sort validated by models.Filters.OrderBy into a
+// GOQU ordered column
+func orderedExpression(sort models.Sort) exp.OrderedExpression {
+	column := goqu.C(string(sort.Column))
+	ordered := column.Asc()
+	if sort.Desc {
+		ordered = column.Desc()
+	}
+	switch sort.Nulls {
+	case models.NullsFirst:
+		return ordered.NullsFirst()
+	case models.NullsLast:
+		return ordered.NullsLast()
+	default:
+		return ordered
+	}
+}
+
+type DAO struct {
+	builder goqu.DialectWrapper
+	db      models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{
+		builder: goqu.Dialect("postgres"),
+		db:      db,
+	}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
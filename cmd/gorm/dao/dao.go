@@ -0,0 +1,231 @@
+/*
+Build and run some queries using the GORM library.
+GORM is a full ORM, with a chainable query builder, struct scanning, hooks,
+associations, and migrations, though only the builder and scanner are used here.
+It is built on database/sql, and its postgres driver uses the database/sql
+version of pgx underneath, so it does not work with PGX natively.
+Some disadvantages:
+  - GORM parses every struct it scans into as a model, and only scans into a
+    field if it recognizes its type, otherwise treating it as an association to
+    another table. It can not scan into models.AccountIdeal, as it does not
+    recognize []int, so it has to scan into models.AccountCompatible, which is
+    then converted to models.AccountIdeal.
+  - Even there, it does not recognize the models.Array[T] and models.JSONB[T]
+    types, despite being a sql.Scanner, so those fields need a gorm type tag.
+    Without it, GORM only logs an error, and silently leaves the fields empty.
+  - The COLORS enum needs no custom type, as models.Color is a string, but it
+    is used anyway to validate it.
+  - It doesn't use postgres' =ANY($1) format for IN queries using a slice, and
+    instead enumerates all values in the slice, so a slice passed to Updates
+    also needs the models.Array[T] wrapper.
+  - A zero time.Time field named CreatedAt is silently replaced with the local
+    time of the Go process on insert, instead of being inserted as given, so it
+    needs the autoCreateTime:false tag.
+  - The table name comes from the struct name, "account_compatibles", so each
+    query has to name the table.
+  - GORM only builds the SQL when the query is run, and resets it afterward, so
+    logging it needs a dry run of the query.
+  - It returns gorm.ErrRecordNotFound instead of sql.ErrNoRows, and logs it.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/veqryn/awesome-go-sql/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	var account models.AccountCompatible
+	err := d.db.WithContext(ctx).
+		Table("accounts").
+		Where("id = ?", id).
+		Take(&account).
+		Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	var accounts []models.AccountCompatible
+	err := d.db.WithContext(ctx).
+		Table("accounts").
+		Order("id").
+		Find(&accounts).
+		Error
+	return models.CompatibleToIdeal(accounts), err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := d.db.WithContext(ctx).
+		Table("accounts")
+
+	// Each chained Where is ANDed onto the previous ones
+	if len(filters.Names) > 0 {
+		query = query.Where("name IN ?", filters.Names)
+	}
+	if filters.Active != nil {
+		query = query.Where("active = ?", *filters.Active)
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		query = query.Where("fav_color IN ?", filters.FavColors)
+	}
+
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		query = query.Where("(created_at, id) > (?, ?)", keyset.CreatedAt, keyset.ID)
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, sort := range sorts {
+		// GORM's clause.OrderByColumn has no NULLS FIRST or LAST
+		query = query.Order(sort.String())
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var accounts []models.AccountCompatible
+	logQuery(query, func(tx *gorm.DB) *gorm.DB { return tx.Find(&accounts) })
+	if err = query.Find(&accounts).Error; err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(models.CompatibleToIdeal(accounts))
+	return ideals, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// GORM flattens embedded structs, and adds the embeddedPrefix to the column
+	// names of the nested struct
+	var rows []struct {
+		models.AccountCompatible
+		Order models.NullOrder `gorm:"embedded;embeddedPrefix:order_"`
+	}
+	err := d.db.WithContext(ctx).
+		Table("accounts AS a").
+		Select(
+			"a.id",
+			"a.name",
+			"a.email",
+			"a.active",
+			"a.fav_color",
+			"a.fav_numbers",
+			"a.properties",
+			"a.created_at",
+			"o.id AS order_id",
+			"o.account_id AS order_account_id",
+			"o.product AS order_product",
+			"o.quantity AS order_quantity",
+			"o.price_cents AS order_price_cents",
+			"o.created_at AS order_created_at").
+		Joins("LEFT JOIN orders AS o ON o.account_id = a.id").
+		Where("a.id = ?", id).
+		Order("o.id").
+		Scan(&rows).
+		Error
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].Ideal()
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	// The zero ID is left out of the insert, because it is the primary key
+	created := account.Compatible()
+	created.ID = 0
+	err := d.db.WithContext(ctx).
+		Table("accounts").
+		Clauses(clause.Returning{}).
+		Create(&created).
+		Error
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.Ideal(), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sets := map[string]any{}
+	if patch.Name != nil {
+		sets["name"] = *patch.Name
+	}
+	if patch.Email != nil {
+		sets["email"] = *patch.Email
+	}
+	if patch.Active != nil {
+		sets["active"] = *patch.Active
+	}
+	if patch.FavColor != nil {
+		sets["fav_color"] = *patch.FavColor
+	}
+	if patch.FavNumbers != nil {
+		sets["fav_numbers"] = models.Array[int](*patch.FavNumbers) // GORM would enumerate a slice as a list
+	}
+	if patch.Properties != nil {
+		sets["properties"] = *patch.Properties
+	}
+
+	// The returned row is scanned into the model
+	var account models.AccountCompatible
+	query := d.db.WithContext(ctx).
+		Table("accounts").
+		Model(&account).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id)
+
+	logQuery(query, func(tx *gorm.DB) *gorm.DB { return tx.Updates(sets) })
+	result := query.Updates(sets)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return models.AccountIdeal{}, false, result.Error
+	}
+	return account.Ideal(), true, nil
+}
+
+// logQuery logs the SQL and args of running the query with fn, as a dry run
+func logQuery(query *gorm.DB, fn func(tx *gorm.DB) *gorm.DB) {
+	stmt := fn(query.Session(&gorm.Session{DryRun: true})).Statement
+	models.LogQuery(stmt.SQL.String(), stmt.Vars)
+}
+
+type DAO struct {
+	db *gorm.DB
+}
+
+// New returns a DAO, which implements models.AccountRepository.
+// GORM uses the same *gorm.DB type for transactions, so it can run against
+// either.
+func New(db *gorm.DB) DAO {
+	return DAO{db: db}
+}
+
+var _ models.AccountRepository = DAO{}
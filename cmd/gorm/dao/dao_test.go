@@ -0,0 +1,39 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/cmd/gorm/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// open wraps the conformance database in GORM
+func open(tb testing.TB) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conformance.DB(tb)}), &gorm.Config{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(open(t)))
+}
+
+func TestTx(t *testing.T) {
+	db := open(t)
+	// GORM has its own transaction helper, that rolls back if fn returns an error
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(ctx, dao.New(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(open(b)))
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	// The builder can not return an error, except when decoding the cursor
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID: func(id uint64) (string, []any, error) {
+			sqlStr, args := selectAccountByIDQuery(id).Sql()
+			return sqlStr, args, nil
+		},
+		SelectAllAccounts: func() (string, []any, error) {
+			sqlStr, args := selectAllAccountsQuery().Sql()
+			return sqlStr, args, nil
+		},
+		SelectAllAccountsByFilter: func(filters models.Filters) (string, []any, error) {
+			query, err := selectAllAccountsByFilterQuery(filters)
+			if err != nil {
+				return "", nil, err
+			}
+			sqlStr, args := query.Sql()
+			return sqlStr, args, nil
+		},
+	})
+}
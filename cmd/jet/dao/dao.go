@@ -0,0 +1,605 @@
+/*
+Build and run some queries using the Jet generator library.
+Jet is a type-safe SQL builder, that also generates models of your existing db
+schema, and can scan directly to those models.
+Jet works by connecting to your database and inspecting the schema, in order to
+generate models.
+This allows its SQL builder to be both type safe and use the actual column names
+defined in your database schema.
+Some disadvantages of it are:
+  - the default schema assumes all postgres arrays and json/jsonb types become
+    golang strings. This apparently can be customized though.
+  - the sql builder can be a bit cumbersome and verbose, owing to its type safety.
+  - the sql builder requires wrappers to support slices.
+  - the sql scanner does not work with PGX.
+    Jet's SQL Builder works with both database/sql and PGX, but the scanner only
+    works with database/sql.
+  - if using just the sql builder with PGX, you can not use the generated models
+    if they contain any arrays.
+  - A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+    for IN queries using a slice, and instead enumerates all values in the slice.
+  - the generated models have to be converted to models.AccountIdeal, see
+    AccountToIdeal.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/go-jet/jet/v2/postgres" // Dot import for fluent sql writing, but optional
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/veqryn/awesome-go-sql/cmd/jet/internal/awesome/public/model"
+	. "github.com/veqryn/awesome-go-sql/cmd/jet/internal/awesome/public/table" // Dot import for fluent sql writing, but optional
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	var account model.Accounts
+	err := selectAccountByIDQuery(id).QueryContext(ctx, d.db, &account)
+
+	switch {
+	case errors.Is(err, qrm.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		ideal, err := AccountToIdeal(account)
+		return ideal, err == nil, err
+	}
+}
+
+func selectAccountByIDQuery(id uint64) Statement {
+	return SELECT(
+		// This would also work: Accounts.AllColumns
+		Accounts.ID,
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).FROM(
+		Accounts,
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	)
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	var accounts []model.Accounts
+	if err := selectAllAccountsQuery().QueryContext(ctx, d.db, &accounts); err != nil {
+		return nil, err
+	}
+	return AccountsToIdeal(accounts)
+}
+
+func selectAllAccountsQuery() Statement {
+	return SELECT(
+		Accounts.AllColumns,
+	).FROM(
+		Accounts,
+	).ORDER_BY(Accounts.ID)
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query, err := selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+
+	queryStr, args := query.Sql()
+	models.LogQuery(queryStr, args)
+
+	var accounts []model.Accounts
+	if err = query.QueryContext(ctx, d.db, &accounts); err != nil {
+		return nil, "", err
+	}
+	ideals, err := AccountsToIdeal(accounts)
+	if err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(ideals)
+	return ideals, next, nil
+}
+
+func selectAllAccountsByFilterQuery(filters models.Filters) (Statement, error) {
+	// Create a slice of conditions (where expressions) dynamically,
+	// then build the SQL statement.
+	var wheres []BoolExpression
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, Accounts.Name.IN(Strings(filters.Names)...))
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, Accounts.Active.EQ(Bool(*filters.Active)))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, err
+		}
+		wheres = append(wheres, Accounts.FavColor.IN(Enums(filters.FavColors)...))
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// Jet has no row value comparison, so it has to be raw sql
+		wheres = append(wheres, RawBool("(accounts.created_at, accounts.id) > (#created_at, #id)",
+			RawArgs{"#created_at": keyset.CreatedAt, "#id": keyset.ID}))
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	query := SELECT(
+		Accounts.AllColumns,
+	).FROM(
+		Accounts,
+	).WHERE(
+		WhereAnd(wheres),
+	).ORDER_BY(
+		OrderBy(sorts)...,
+	)
+	if limit := filters.Limit(); limit > 0 {
+		query = query.LIMIT(int64(limit))
+	}
+	return query, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	query := SELECT(
+		Accounts.AllColumns,
+		Orders.AllColumns,
+	).FROM(
+		Accounts.LEFT_JOIN(Orders, Orders.AccountID.EQ(Accounts.ID)),
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	).ORDER_BY(Orders.ID)
+
+	// Jet's QRM groups the joined rows by the primary key of each model, so
+	// the orders are nested under their account without any folding by hand.
+	// The orders of the LEFT JOIN that are all NULL are skipped.
+	var account struct {
+		model.Accounts
+		Orders []model.Orders
+	}
+	err := query.QueryContext(ctx, d.db, &account)
+
+	switch {
+	case errors.Is(err, qrm.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		ideal, err := AccountToIdeal(account.Accounts)
+		ideal.Orders = OrdersToIdeal(account.Orders)
+		return ideal, err == nil, err
+	}
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	query := Accounts.INSERT(
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).VALUES(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers, // Raw values become placeholder args, so slices do not need a wrapper
+		account.Properties,
+		account.CreatedAt,
+	).RETURNING(
+		Accounts.AllColumns,
+	)
+
+	var created model.Accounts
+	if err := query.QueryContext(ctx, d.db, &created); err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return AccountToIdeal(created)
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	query := updateAccountQuery(id, patch)
+
+	queryStr, args := query.Sql()
+	models.LogQuery(queryStr, args)
+
+	var account model.Accounts
+	err := query.QueryContext(ctx, d.db, &account)
+
+	switch {
+	case errors.Is(err, qrm.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		ideal, err := AccountToIdeal(account)
+		return ideal, err == nil, err
+	}
+}
+
+func updateAccountQuery(id uint64, patch models.AccountPatch) Statement {
+	// Create slices of columns and their values dynamically.
+	// The typed column.SET(expression) form casts strings to text, which
+	// postgres will not assign to enum, array, or jsonb columns, so raw values
+	// are used instead, which become placeholder args.
+	var columns ColumnList
+	var values []any
+	if patch.Name != nil {
+		columns = append(columns, Accounts.Name)
+		values = append(values, *patch.Name)
+	}
+	if patch.Email != nil {
+		columns = append(columns, Accounts.Email)
+		values = append(values, *patch.Email)
+	}
+	if patch.Active != nil {
+		columns = append(columns, Accounts.Active)
+		values = append(values, *patch.Active)
+	}
+	if patch.FavColor != nil {
+		columns = append(columns, Accounts.FavColor)
+		values = append(values, *patch.FavColor)
+	}
+	if patch.FavNumbers != nil {
+		columns = append(columns, Accounts.FavNumbers)
+		values = append(values, *patch.FavNumbers)
+	}
+	if patch.Properties != nil {
+		columns = append(columns, Accounts.Properties)
+		values = append(values, *patch.Properties)
+	}
+
+	return Accounts.UPDATE(
+		columns,
+	).SET(
+		values[0], values[1:]...,
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	).RETURNING(
+		Accounts.AllColumns,
+	)
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	query := Accounts.INSERT(
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).VALUES(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers, // Raw values become placeholder args, so slices do not need a wrapper
+		account.Properties,
+		account.CreatedAt,
+	).ON_CONFLICT(
+		Accounts.Email,
+	).DO_UPDATE(SET(
+		Accounts.Name.SET(Accounts.EXCLUDED.Name),
+		Accounts.Active.SET(Accounts.EXCLUDED.Active),
+		Accounts.FavColor.SET(Accounts.EXCLUDED.FavColor),
+		Accounts.FavNumbers.SET(Accounts.EXCLUDED.FavNumbers),
+		Accounts.Properties.SET(Accounts.EXCLUDED.Properties),
+	)).RETURNING(
+		Accounts.AllColumns,
+		RawBool("xmax = 0").AS("inserted"), // xmax is only zero if the row was inserted, not updated
+	)
+
+	// Fields of an anonymous struct are matched to the column alias
+	var upserted struct {
+		model.Accounts
+		Inserted bool
+	}
+	if err := query.QueryContext(ctx, d.db, &upserted); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	ideal, err := AccountToIdeal(upserted.Accounts)
+	return ideal, upserted.Inserted, err
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// Each account uses 7 bind parameters, so chunk to stay under the limit
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		rows, err := IdealsToAccounts(chunk)
+		if err != nil {
+			return total, err
+		}
+
+		// MODELS adds a row per model, using the struct fields that match
+		// the columns being inserted
+		query := Accounts.INSERT(
+			Accounts.MutableColumns,
+		).MODELS(
+			rows,
+		)
+
+		result, err := query.ExecContext(ctx, d.db)
+		if err != nil {
+			return total, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// json_agg aggregates each account's tags into a json array, which is NULL
+	// for an account without any tags.
+	// Jet has no json_agg function, so it has to be written as raw SQL.
+	tags := SELECT(
+		Raw("json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name)"),
+	).FROM(
+		AccountTags.INNER_JOIN(Tags, Tags.ID.EQ(AccountTags.TagID)),
+	).WHERE(
+		AccountTags.AccountID.EQ(Accounts.ID),
+	)
+
+	query := SELECT(
+		Accounts.AllColumns,
+		tags.AS("tags"),
+	).FROM(
+		Accounts,
+	).ORDER_BY(Accounts.ID)
+
+	// The scanner only works with database/sql, which hands the json array
+	// over as bytes, so it needs the models.Tags wrapper to unmarshal it
+	var rows []struct {
+		model.Accounts
+		Tags models.Tags
+	}
+	if err := query.QueryContext(ctx, d.db, &rows); err != nil || rows == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(rows))
+	for _, row := range rows {
+		account, err := AccountToIdeal(row.Accounts)
+		if err != nil {
+			return nil, err
+		}
+		account.Tags = row.Tags
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db models.SQLQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, models.AccountBulkInserter, and
+// models.AccountTagSelector.
+// It can run against either a *sql.DB or a *sql.Tx.
+func New(db models.SQLQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+	_ models.AccountTagSelector  = DAO{}
+)
+
+// Integers converts slice of integers into slice of jet.Expression, useful for IN queries.
+func Integers[T ~int | ~int8 | ~int16 | ~int32 | ~int64](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(Int(int64(v))))
+	}
+	return expressions
+}
+
+// Strings converts slice of strings into slice of jet.Expression's, useful for IN queries.
+func Strings[T ~string](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(String(string(v))))
+	}
+	return expressions
+}
+
+// Enums converts slice of strings into slice of jet.Expression's, useful for IN queries.
+func Enums[T ~string](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(NewEnumValue(string(v))))
+	}
+	return expressions
+}
+
+// WhereAnd joins multiple jet.BoolExpression together with AND
+func WhereAnd(wheres []BoolExpression) BoolExpression {
+	var where BoolExpression
+	for _, w := range wheres {
+		if where == nil {
+			where = w
+		} else {
+			where = where.AND(w)
+		}
+	}
+	return where
+}
+
+// sortColumns maps the whitelisted sort columns to their typed jet columns
+var sortColumns = map[models.SortColumn]Column{
+	models.SortByID:        Accounts.ID,
+	models.SortByName:      Accounts.Name,
+	models.SortByEmail:     Accounts.Email,
+	models.SortByActive:    Accounts.Active,
+	models.SortByFavColor:  Accounts.FavColor,
+	models.SortByCreatedAt: Accounts.CreatedAt,
+}
+
+// OrderBy converts sorts validated by models.Filters.OrderBy into jet
+// ORDER BY clauses on the typed columns
+func OrderBy(sorts []models.Sort) []OrderByClause {
+	clauses := make([]OrderByClause, 0, len(sorts))
+	for _, sort := range sorts {
+		clause := sortColumns[sort.Column].ASC()
+		if sort.Desc {
+			clause = sortColumns[sort.Column].DESC()
+		}
+		switch sort.Nulls {
+		case models.NullsFirst:
+			clause = clause.NULLS_FIRST()
+		case models.NullsLast:
+			clause = clause.NULLS_LAST()
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// AccountToIdeal converts the generated account model to an AccountIdeal.
+// The generated model uses strings for the array and jsonb columns, so they
+// have to be parsed using the same wrappers used for scanning them.
+func AccountToIdeal(a model.Accounts) (models.AccountIdeal, error) {
+	var favNumbers models.Array[int]
+	if a.FavNumbers != nil {
+		if err := favNumbers.Scan(*a.FavNumbers); err != nil {
+			return models.AccountIdeal{}, err
+		}
+	}
+
+	var favColor *models.Color
+	if a.FavColor != nil {
+		color := models.Color(a.FavColor.String())
+		favColor = &color
+	}
+
+	var properties models.JSONB[models.AccountProperties]
+	if a.Properties != nil {
+		if err := properties.Scan(*a.Properties); err != nil {
+			return models.AccountIdeal{}, err
+		}
+	}
+
+	return models.AccountIdeal{
+		ID:         uint64(a.ID),
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   favColor,
+		FavNumbers: favNumbers.Get(),
+		Properties: properties,
+		CreatedAt:  a.CreatedAt,
+	}, nil
+}
+
+// AccountsToIdeal converts a slice of the generated account model to AccountIdeal
+func AccountsToIdeal(accounts []model.Accounts) ([]models.AccountIdeal, error) {
+	if accounts == nil {
+		return nil, nil
+	}
+	ideals := make([]models.AccountIdeal, 0, len(accounts))
+	for _, account := range accounts {
+		ideal, err := AccountToIdeal(account)
+		if err != nil {
+			return nil, err
+		}
+		ideals = append(ideals, ideal)
+	}
+	return ideals, nil
+}
+
+// OrdersToIdeal converts a slice of the generated order model to models.Order
+func OrdersToIdeal(orders []model.Orders) []models.Order {
+	if len(orders) == 0 {
+		return nil
+	}
+	ideals := make([]models.Order, 0, len(orders))
+	for _, o := range orders {
+		ideals = append(ideals, models.Order{
+			ID:         uint64(o.ID),
+			AccountID:  uint64(o.AccountID),
+			Product:    o.Product,
+			Quantity:   int(o.Quantity),
+			PriceCents: o.PriceCents,
+			CreatedAt:  o.CreatedAt,
+		})
+	}
+	return ideals
+}
+
+// IdealToAccount converts an AccountIdeal to the generated account model.
+// The generated model uses strings for the array and jsonb columns, so they
+// have to be formatted using the same wrappers used for scanning them.
+func IdealToAccount(a models.AccountIdeal) (model.Accounts, error) {
+	var favNumbers *string
+	if a.FavNumbers != nil {
+		value, err := models.Array[int](a.FavNumbers).Value()
+		if err != nil {
+			return model.Accounts{}, err
+		}
+		str := value.(string)
+		favNumbers = &str
+	}
+
+	var favColor *model.Colors
+	if a.FavColor != nil {
+		color := model.Colors(*a.FavColor)
+		favColor = &color
+	}
+
+	var properties *string
+	if a.Properties.Valid {
+		value, err := a.Properties.Value()
+		if err != nil {
+			return model.Accounts{}, err
+		}
+		str := value.(string)
+		properties = &str
+	}
+
+	return model.Accounts{
+		ID:         int64(a.ID),
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   favColor,
+		FavNumbers: favNumbers,
+		Properties: properties,
+		CreatedAt:  a.CreatedAt,
+	}, nil
+}
+
+// IdealsToAccounts converts a slice of AccountIdeal to the generated account model
+func IdealsToAccounts(accounts []models.AccountIdeal) ([]model.Accounts, error) {
+	rows := make([]model.Accounts, 0, len(accounts))
+	for _, account := range accounts {
+		row, err := IdealToAccount(account)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
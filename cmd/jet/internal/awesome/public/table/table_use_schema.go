@@ -10,5 +10,8 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	AccountTags = AccountTags.FromSchema(schema)
 	Accounts = Accounts.FromSchema(schema)
+	Orders = Orders.FromSchema(schema)
+	Tags = Tags.FromSchema(schema)
 }
@@ -0,0 +1,588 @@
+/*
+Build and run some queries using the Jet generator library.
+Jet is a type-safe SQL builder, that also generates models of your existing db
+schema, and can scan directly to those models.
+Jet works by connecting to your database and inspecting the schema, in order to
+generate models.
+This allows its SQL builder to be both type safe and use the actual column names
+defined in your database schema.
+Some disadvantages of it are:
+  - the default schema assumes all postgres arrays and json/jsonb types become
+    golang strings. This apparently can be customized though.
+  - the sql builder can be a bit cumbersome and verbose, owing to its type safety.
+  - the sql builder requires wrappers to support slices.
+  - the sql scanner does not work with PGX.
+    Jet's SQL Builder works with both database/sql and PGX, but the scanner only
+    works with database/sql.
+  - if using just the sql builder with PGX, you can not use the generated models
+    if they contain any arrays.
+  - A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+    for IN queries using a slice, and instead enumerates all values in the slice.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/go-jet/jet/v2/postgres"                                      // Dot import for fluent sql writing, but optional
+	"github.com/jackc/pgx/v5"                                                  // DB Driver
+	. "github.com/veqryn/awesome-go-sql/cmd/jet/internal/awesome/public/table" // Dot import for fluent sql writing, but optional
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args := selectAccountByIDQuery(id)
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// selectAccountByIDQuery builds the SQL separately from running it, so that
+// building can be benchmarked on its own.
+func selectAccountByIDQuery(id uint64) (string, []any) {
+	query := SELECT(
+		// This would also work: Accounts.AllColumns
+		Accounts.ID,
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).FROM(
+		Accounts,
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	)
+
+	return query.Sql()
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	sqlStr, args := selectAllAccountsQuery()
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func selectAllAccountsQuery() (string, []any) {
+	query := SELECT(
+		Accounts.AllColumns,
+	).FROM(
+		Accounts,
+	).ORDER_BY(Accounts.ID)
+
+	return query.Sql()
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, args)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, "", scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	// Create a slice of conditions (where expressions) dynamically,
+	// then build the SQL statement.
+	var wheres []BoolExpression
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, Accounts.Name.IN(Strings(filters.Names)...))
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, Accounts.Active.EQ(Bool(*filters.Active)))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		wheres = append(wheres, Accounts.FavColor.IN(Enums(filters.FavColors)...))
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		// Jet has no row value comparison, so it has to be raw sql
+		wheres = append(wheres, RawBool("(accounts.created_at, accounts.id) > (#created_at, #id)",
+			RawArgs{"#created_at": keyset.CreatedAt, "#id": keyset.ID}))
+	}
+
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := SELECT(
+		Accounts.AllColumns,
+	).FROM(
+		Accounts,
+	).WHERE(
+		WhereAnd(wheres),
+	).ORDER_BY(
+		OrderBy(sorts)...,
+	)
+	if limit := filters.Limit(); limit > 0 {
+		query = query.LIMIT(int64(limit))
+	}
+
+	sqlStr, args := query.Sql()
+	return sqlStr, args, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args := selectAccountWithOrdersQuery(id)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	defer rows.Close()
+
+	// Every row repeats the account columns, with the columns of one order,
+	// so the rows have to be folded into a single account by hand
+	var account models.AccountIdeal
+	var found bool
+	for rows.Next() {
+		var order models.NullOrder
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&order.ID, // The LEFT JOIN makes every order column nullable
+			&order.AccountID,
+			&order.Product,
+			&order.Quantity,
+			&order.PriceCents,
+			&order.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return models.AccountIdeal{}, false, scanErr
+		}
+		found = true
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return account, found, nil
+}
+
+func selectAccountWithOrdersQuery(id uint64) (string, []any) {
+	// The scanner that nests the orders under the account only works with
+	// database/sql, so the columns are listed in the order they are scanned
+	query := SELECT(
+		Accounts.ID,
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+		Orders.ID,
+		Orders.AccountID,
+		Orders.Product,
+		Orders.Quantity,
+		Orders.PriceCents,
+		Orders.CreatedAt,
+	).FROM(
+		Accounts.LEFT_JOIN(Orders, Orders.AccountID.EQ(Accounts.ID)),
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	).ORDER_BY(Orders.ID)
+
+	return query.Sql()
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	sqlStr, args := createAccountQuery(account)
+
+	var created models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.Active,
+		&created.FavColor,
+		&created.FavNumbers,
+		&created.Properties,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func createAccountQuery(account models.AccountIdeal) (string, []any) {
+	query := Accounts.INSERT(
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).VALUES(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers, // Raw values become placeholder args, so slices do not need a wrapper
+		account.Properties,
+		account.CreatedAt,
+	).RETURNING(
+		Accounts.AllColumns,
+	)
+
+	return query.Sql()
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sqlStr, args := updateAccountQuery(id, patch)
+	models.LogQuery(sqlStr, args)
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func updateAccountQuery(id uint64, patch models.AccountPatch) (string, []any) {
+	// Create slices of columns and their values dynamically.
+	// The typed column.SET(expression) form casts strings to text, which
+	// postgres will not assign to enum, array, or jsonb columns, so raw values
+	// are used instead, which become placeholder args.
+	var columns ColumnList
+	var values []any
+	if patch.Name != nil {
+		columns = append(columns, Accounts.Name)
+		values = append(values, *patch.Name)
+	}
+	if patch.Email != nil {
+		columns = append(columns, Accounts.Email)
+		values = append(values, *patch.Email)
+	}
+	if patch.Active != nil {
+		columns = append(columns, Accounts.Active)
+		values = append(values, *patch.Active)
+	}
+	if patch.FavColor != nil {
+		columns = append(columns, Accounts.FavColor)
+		values = append(values, *patch.FavColor)
+	}
+	if patch.FavNumbers != nil {
+		columns = append(columns, Accounts.FavNumbers)
+		values = append(values, *patch.FavNumbers)
+	}
+	if patch.Properties != nil {
+		columns = append(columns, Accounts.Properties)
+		values = append(values, *patch.Properties)
+	}
+
+	query := Accounts.UPDATE(
+		columns,
+	).SET(
+		values[0], values[1:]...,
+	).WHERE(
+		Accounts.ID.EQ(Uint64(id)),
+	).RETURNING(
+		Accounts.AllColumns,
+	)
+
+	return query.Sql()
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	sqlStr, args := upsertAccountByEmailQuery(account)
+
+	var upserted models.AccountIdeal
+	var inserted bool
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&upserted.ID,
+		&upserted.Name,
+		&upserted.Email,
+		&upserted.Active,
+		&upserted.FavColor,
+		&upserted.FavNumbers,
+		&upserted.Properties,
+		&upserted.CreatedAt,
+		&inserted,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted, inserted, nil
+}
+
+func upsertAccountByEmailQuery(account models.AccountIdeal) (string, []any) {
+	query := Accounts.INSERT(
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	).VALUES(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers, // Raw values become placeholder args, so slices do not need a wrapper
+		account.Properties,
+		account.CreatedAt,
+	).ON_CONFLICT(
+		Accounts.Email,
+	).DO_UPDATE(SET(
+		Accounts.Name.SET(Accounts.EXCLUDED.Name),
+		Accounts.Active.SET(Accounts.EXCLUDED.Active),
+		Accounts.FavColor.SET(Accounts.EXCLUDED.FavColor),
+		Accounts.FavNumbers.SET(Accounts.EXCLUDED.FavNumbers),
+		Accounts.Properties.SET(Accounts.EXCLUDED.Properties),
+	)).RETURNING(
+		Accounts.AllColumns,
+		RawBool("xmax = 0").AS("inserted"), // xmax is only zero if the row was inserted, not updated
+	)
+
+	return query.Sql()
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// Each account uses 7 bind parameters, so chunk to stay under the limit
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		sqlStr, args := bulkInsertAccountsQuery(chunk)
+
+		tag, err := d.db.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
+func bulkInsertAccountsQuery(accounts []models.AccountIdeal) (string, []any) {
+	query := Accounts.INSERT(
+		Accounts.Name,
+		Accounts.Email,
+		Accounts.Active,
+		Accounts.FavColor,
+		Accounts.FavNumbers,
+		Accounts.Properties,
+		Accounts.CreatedAt,
+	)
+
+	// The generated models can not be used with PGX because they contain an
+	// array, so MODELS can not be used either. Each call to VALUES adds a row.
+	for _, account := range accounts {
+		query = query.VALUES(
+			account.Name,
+			account.Email,
+			account.Active,
+			account.FavColor,
+			account.FavNumbers, // Raw values become placeholder args, so slices do not need a wrapper
+			account.Properties,
+			account.CreatedAt,
+		)
+	}
+
+	return query.Sql()
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
+
+// Integers converts slice of integers into slice of jet.Expression, useful for IN queries.
+func Integers[T ~int | ~int8 | ~int16 | ~int32 | ~int64](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(Int(int64(v))))
+	}
+	return expressions
+}
+
+// Strings converts slice of strings into slice of jet.Expression's, useful for IN queries.
+func Strings[T ~string](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(String(string(v))))
+	}
+	return expressions
+}
+
+// Enums converts slice of strings into slice of jet.Expression's, useful for IN queries.
+func Enums[T ~string](s []T) []Expression {
+	expressions := make([]Expression, 0, len(s))
+	for _, v := range s {
+		expressions = append(expressions, Expression(NewEnumValue(string(v))))
+	}
+	return expressions
+}
+
+// WhereAnd joins multiple jet.BoolExpression together with AND
+func WhereAnd(wheres []BoolExpression) BoolExpression {
+	var where BoolExpression
+	for _, w := range wheres {
+		if where == nil {
+			where = w
+		} else {
+			where = where.AND(w)
+		}
+	}
+	return where
+}
+
+// sortColumns maps the whitelisted sort columns to their typed jet columns
+var sortColumns = map[models.SortColumn]Column{
+	models.SortByID:        Accounts.ID,
+	models.SortByName:      Accounts.Name,
+	models.SortByEmail:     Accounts.Email,
+	models.SortByActive:    Accounts.Active,
+	models.SortByFavColor:  Accounts.FavColor,
+	models.SortByCreatedAt: Accounts.CreatedAt,
+}
+
+// OrderBy converts sorts validated by models.Filters.OrderBy into jet
+// ORDER BY clauses on the typed columns
+func OrderBy(sorts []models.Sort) []OrderByClause {
+	clauses := make([]OrderByClause, 0, len(sorts))
+	for _, sort := range sorts {
+		clause := sortColumns[sort.Column].ASC()
+		if sort.Desc {
+			clause = sortColumns[sort.Column].DESC()
+		}
+		switch sort.Nulls {
+		case models.NullsFirst:
+			clause = clause.NULLS_FIRST()
+		case models.NullsLast:
+			clause = clause.NULLS_LAST()
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
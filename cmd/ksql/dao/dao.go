@@ -0,0 +1,262 @@
+/*
+Build and run some queries using the KSQL library.
+KSQL is similar to SQLX in that it just scans the results into structs/slices.
+Like SQLX, it wraps the database/sql package's DB object, though it provides a
+much smaller and more usable API.
+Because scan is only concerned with querying and scanning, it can be combined
+with a query builder library that provides the actual query strings.
+KSQL provides adapters for many drivers, including PGX.
+Its Insert only reads back the ID, not any other columns set by the database.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/veqryn/awesome-go-sql/models"
+	"github.com/vingarcia/ksql"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	var account models.AccountIdeal
+	err := d.db.QueryOne(ctx, &account, query, id)
+	switch {
+	case errors.Is(err, ksql.ErrRecordNotFound):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	var accounts []models.AccountIdeal
+	err := d.db.Query(ctx, &accounts, query)
+	return accounts, err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	var accounts []models.AccountIdeal
+	if err = d.db.Query(ctx, &accounts, query, args...); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+// accountsTable tells KSQL the table name and its ID column
+var accountsTable = ksql.NewTable("accounts", "id")
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// KSQL generates the SELECT clause of a join from the tablename tags,
+	// so the query starts with FROM
+	const query = `
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	var rows []struct {
+		Account models.AccountIdeal `tablename:"a"`
+		Order   models.NullOrder    `tablename:"o"`
+	}
+	if err := d.db.Query(ctx, &rows, query, id); err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].Account
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	// KSQL builds the insert from the struct tags, and uses RETURNING to fill
+	// in the ID, but it does not read back any other columns.
+	account.ID = 0
+	if err := d.db.Insert(ctx, accountsTable, &account); err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return account, nil
+}
+
+// accountPatch adds the struct tags and ID that KSQL needs to models.AccountPatch
+type accountPatch struct {
+	ID         uint64                                  `ksql:"id"`
+	Name       *string                                 `ksql:"name"`
+	Email      *string                                 `ksql:"email"`
+	Active     *bool                                   `ksql:"active"`
+	FavColor   **models.Color                          `ksql:"fav_color"`
+	FavNumbers *[]int                                  `ksql:"fav_numbers"`
+	Properties *models.JSONB[models.AccountProperties] `ksql:"properties"`
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// KSQL's Patch builds the SET from only the non-nil pointer fields, but it
+	// does not support RETURNING, so the updated row has to be selected after.
+	err := d.db.Patch(ctx, accountsTable, accountPatch{
+		ID:         id,
+		Name:       patch.Name,
+		Email:      patch.Email,
+		Active:     patch.Active,
+		FavColor:   patch.FavColor,
+		FavNumbers: patch.FavNumbers,
+		Properties: patch.Properties,
+	})
+	switch {
+	case errors.Is(err, ksql.ErrRecordNotFound):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return d.SelectAccountByID(ctx, id)
+	}
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// KSQL generates the SELECT clause of a join from the tablename tags, so
+	// the json_agg is done in a LATERAL subselect that can be given a table
+	// name. An aggregate without a GROUP BY always returns one row, so the
+	// json array is NULL for an account without any tags.
+	const query = `
+		FROM accounts a
+		CROSS JOIN LATERAL (
+			SELECT json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name) AS tags
+			FROM account_tags
+			JOIN tags ON tags.id = account_tags.tag_id
+			WHERE account_tags.account_id = a.id
+		) t
+		ORDER BY a.id`
+
+	// The json modifier unmarshals the json array straight into []models.Tag
+	var rows []struct {
+		Account models.AccountIdeal `tablename:"a"`
+		Tags    struct {
+			Tags []models.Tag `ksql:"tags,json"`
+		} `tablename:"t"`
+	}
+	if err := d.db.Query(ctx, &rows, query); err != nil || rows == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(rows))
+	for _, row := range rows {
+		row.Account.Tags = row.Tags.Tags
+		accounts = append(accounts, row.Account)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db ksql.Provider // Wrap the db connection
+}
+
+// New returns a DAO, which implements models.AccountRepository and
+// models.AccountTagSelector.
+// It can run against either a ksql.DB or the ksql.Provider given to the
+// function passed to ksql.DB.Transaction.
+func New(db ksql.Provider) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository  = DAO{}
+	_ models.AccountTagSelector = DAO{}
+)
@@ -0,0 +1,41 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/cmd/ksql/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+	"github.com/vingarcia/ksql"
+	kpgx "github.com/vingarcia/ksql/adapters/kpgx5"
+)
+
+func TestConformance(t *testing.T) {
+	db, err := kpgx.NewFromPgxPool(conformance.Pool(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conformance.Run(t, dao.New(db))
+}
+
+func TestTx(t *testing.T) {
+	db, err := kpgx.NewFromPgxPool(conformance.Pool(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// KSQL has its own transaction helper, that rolls back if fn returns an error
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return db.Transaction(ctx, func(tx ksql.Provider) error {
+			return fn(ctx, dao.New(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	db, err := kpgx.NewFromPgxPool(conformance.Pool(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	conformance.Bench(b, dao.New(db))
+}
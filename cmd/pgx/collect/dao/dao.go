@@ -0,0 +1,365 @@
+/*
+Build and run some queries using only pgx, with its generic CollectRows and
+CollectOneRow helpers, instead of scanning each column by hand.
+The RowTo functions scan each row into a struct, by matching the columns to the
+db struct tags (RowToStructByName), or by their position (RowToStructByPos).
+Compared to scany:
+  - Missing and extra columns: RowToStructByName returns an error if any column
+    does not have a struct field, or any struct field does not have a column.
+    RowToStructByNameLax allows struct fields without a column, which is how
+    scany behaves by default, but neither allows a column without a struct
+    field, which scany can with its AllowUnknownColumns option.
+  - Embedded structs: anonymous embedded structs are flattened, like scany, but
+    a named struct field is treated as a single column, so the columns of a
+    nested struct can not be mapped by a prefix like scany's "order.id".
+    RowToAddrOfStructByPos can scan a join into two embedded structs instead,
+    as long as the columns are selected in the same order as the fields.
+  - NULL handling: exactly the same as scany, as both scan with pgx's own type
+    system, so NULL needs a pointer, a nil-able slice, or a scanner like
+    models.JSONB[T].
+
+It needs nothing beyond pgx itself, and the dynamic queries still have to be
+built by hand.
+The error from Query can be ignored, as the returned pgx.Rows carries it, and
+CollectRows and CollectOneRow return it.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	rows, _ := d.db.Query(ctx, query, id)
+	account, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AccountIdeal])
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	rows, _ := d.db.Query(ctx, query)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.AccountIdeal])
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	rows, _ := d.db.Query(ctx, query, args...)
+	accounts, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AccountIdeal])
+	if err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// RowToStructByName can not map the columns of a nested struct, and both
+	// structs have an "id", so the columns are scanned by position instead
+	const query = `
+		SELECT
+			a.id,
+			a.name,
+			a.email,
+			a.active,
+			a.fav_color,
+			a.fav_numbers,
+			a.properties,
+			a.created_at,
+			o.id,
+			o.account_id,
+			o.product,
+			o.quantity,
+			o.price_cents,
+			o.created_at
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	// Both structs are embedded, because RowToAddrOfStructByPos only flattens
+	// anonymous embedded structs, matching their fields to the columns in order,
+	// while a named struct field would be scanned as a single column.
+	// The order is embedded one level deeper, so that the account's fields, such
+	// as ID, are still promoted instead of being ambiguous with the order's.
+	type order struct {
+		models.NullOrder
+	}
+	type accountOrder struct {
+		models.AccountIdeal
+		order
+	}
+	rows, _ := d.db.Query(ctx, query, id)
+	accountOrders, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[accountOrder])
+	if err != nil || len(accountOrders) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := accountOrders[0].AccountIdeal
+	for _, row := range accountOrders {
+		if order, ok := row.NullOrder.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`
+
+	rows, _ := d.db.Query(ctx, query,
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt,
+	)
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AccountIdeal])
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Sadly, we have to manually build dynamic updates too
+	var sets []string
+	var args []any
+	argCount := 1
+	if patch.Name != nil {
+		sets = append(sets, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *patch.Name)
+		argCount++
+	}
+	if patch.Email != nil {
+		sets = append(sets, fmt.Sprintf("email = $%d", argCount))
+		args = append(args, *patch.Email)
+		argCount++
+	}
+	if patch.Active != nil {
+		sets = append(sets, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *patch.Active)
+		argCount++
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, fmt.Sprintf("fav_color = $%d", argCount))
+		args = append(args, *patch.FavColor)
+		argCount++
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, fmt.Sprintf("fav_numbers = $%d", argCount))
+		args = append(args, *patch.FavNumbers)
+		argCount++
+	}
+	if patch.Properties != nil {
+		sets = append(sets, fmt.Sprintf("properties = $%d", argCount))
+		args = append(args, *patch.Properties)
+		argCount++
+	}
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`, strings.Join(sets, ", "), argCount)
+	models.LogQuery(query, args)
+
+	rows, _ := d.db.Query(ctx, query, args...)
+	account, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AccountIdeal])
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// json_agg aggregates each account's tags into a json array, which is NULL
+	// for an account without any tags
+	const query = `
+		SELECT
+			accounts.id,
+			accounts.name,
+			accounts.email,
+			accounts.active,
+			accounts.fav_color,
+			accounts.fav_numbers,
+			accounts.properties,
+			accounts.created_at,
+			(
+				SELECT json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name)
+				FROM account_tags
+				JOIN tags ON tags.id = account_tags.tag_id
+				WHERE account_tags.account_id = accounts.id
+			) AS tags
+		FROM accounts
+		ORDER BY accounts.id`
+
+	// pgx unmarshals a json column into any type, so the json array can be
+	// scanned straight into []models.Tag.
+	// The embedded Tags field is tagged db:"-", so it does not clash with this one.
+	type accountTags struct {
+		models.AccountIdeal
+		Tags []models.Tag `db:"tags"`
+	}
+	rows, _ := d.db.Query(ctx, query)
+	accountsTags, err := pgx.CollectRows(rows, pgx.RowToStructByName[accountTags])
+	if err != nil || accountsTags == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(accountsTags))
+	for _, row := range accountsTags {
+		row.AccountIdeal.Tags = row.Tags
+		accounts = append(accounts, row.AccountIdeal)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository and
+// models.AccountTagSelector.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository  = DAO{}
+	_ models.AccountTagSelector = DAO{}
+)
@@ -0,0 +1,82 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/veqryn/awesome-go-sql/cmd/pgx/collect/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+	"github.com/veqryn/awesome-go-sql/txn"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(conformance.Pool(t)))
+}
+
+// TestRowToStructByNameColumns shows that RowToStructByName, and even
+// RowToStructByNameLax, fail on a column without a struct field, unlike scany
+// with its AllowUnknownColumns option
+func TestRowToStructByNameColumns(t *testing.T) {
+	columns := []string{"id", "name", "email", "active", "fav_color", "fav_numbers", "properties", "created_at"}
+
+	testCases := []struct {
+		name    string
+		columns []string
+		rowTo   pgx.RowToFunc[models.AccountIdeal]
+		wantErr bool
+	}{
+		{"exact", columns, pgx.RowToStructByName[models.AccountIdeal], false},
+		{"extra", append(columns, "extra"), pgx.RowToStructByName[models.AccountIdeal], true},
+		{"extra lax", append(columns, "extra"), pgx.RowToStructByNameLax[models.AccountIdeal], true},
+		{"missing", columns[1:], pgx.RowToStructByName[models.AccountIdeal], true},
+		{"missing lax", columns[1:], pgx.RowToStructByNameLax[models.AccountIdeal], false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.rowTo(fakeRow(tc.columns))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("expected an error %t, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// fakeRow is a pgx.CollectableRow with the named columns, which scans nothing,
+// as the RowTo functions match the columns to the struct fields before scanning
+type fakeRow []string
+
+func (r fakeRow) FieldDescriptions() []pgconn.FieldDescription {
+	descriptions := make([]pgconn.FieldDescription, 0, len(r))
+	for _, name := range r {
+		descriptions = append(descriptions, pgconn.FieldDescription{Name: name})
+	}
+	return descriptions
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	return nil
+}
+
+func (r fakeRow) Values() ([]any, error) {
+	return make([]any, len(r)), nil
+}
+
+func (r fakeRow) RawValues() [][]byte {
+	return make([][]byte, len(r))
+}
+
+func TestTx(t *testing.T) {
+	db := conformance.Pool(t)
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		return txn.WithPgxTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+			return fn(ctx, dao.New(tx))
+		})
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(conformance.Pool(b)))
+}
@@ -0,0 +1,549 @@
+/*
+Build and run some queries using the pgx package (the latest and most popular
+golang postgres driver).
+Almost the same as the standard library version, but does not use database/sql,
+and therefore has its own interface, though it is almost identical.
+It automatically scans into all types that we expect, without additional
+wrappers.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, query, id).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	rows, err := d.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	rows, err := d.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, "", scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			a.id,
+			a.name,
+			a.email,
+			a.active,
+			a.fav_color,
+			a.fav_numbers,
+			a.properties,
+			a.created_at,
+			o.id,
+			o.account_id,
+			o.product,
+			o.quantity,
+			o.price_cents,
+			o.created_at
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	rows, err := d.db.Query(ctx, query, id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	defer rows.Close()
+
+	// Every row repeats the account columns, with the columns of one order,
+	// so the rows have to be folded into a single account by hand
+	var account models.AccountIdeal
+	var found bool
+	for rows.Next() {
+		var order models.NullOrder
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&order.ID, // The LEFT JOIN makes every order column nullable
+			&order.AccountID,
+			&order.Product,
+			&order.Quantity,
+			&order.PriceCents,
+			&order.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return models.AccountIdeal{}, false, scanErr
+		}
+		found = true
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return account, found, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`
+
+	var created models.AccountIdeal
+	err := d.db.QueryRow(ctx, query,
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt,
+	).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.Active,
+		&created.FavColor,
+		&created.FavNumbers,
+		&created.Properties,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Sadly, we have to manually build dynamic updates too
+	var sets []string
+	var args []any
+	argCount := 1
+	if patch.Name != nil {
+		sets = append(sets, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *patch.Name)
+		argCount++
+	}
+	if patch.Email != nil {
+		sets = append(sets, fmt.Sprintf("email = $%d", argCount))
+		args = append(args, *patch.Email)
+		argCount++
+	}
+	if patch.Active != nil {
+		sets = append(sets, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *patch.Active)
+		argCount++
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, fmt.Sprintf("fav_color = $%d", argCount))
+		args = append(args, *patch.FavColor)
+		argCount++
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, fmt.Sprintf("fav_numbers = $%d", argCount))
+		args = append(args, *patch.FavNumbers)
+		argCount++
+	}
+	if patch.Properties != nil {
+		sets = append(sets, fmt.Sprintf("properties = $%d", argCount))
+		args = append(args, *patch.Properties)
+		argCount++
+	}
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`, strings.Join(sets, ", "), argCount)
+	models.LogQuery(query, args)
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, query, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	// xmax is only zero if the row was inserted, not updated
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (email) DO UPDATE SET
+			name = EXCLUDED.name,
+			active = EXCLUDED.active,
+			fav_color = EXCLUDED.fav_color,
+			fav_numbers = EXCLUDED.fav_numbers,
+			properties = EXCLUDED.properties
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at,
+			(xmax = 0) AS inserted`
+
+	var upserted models.AccountIdeal
+	var inserted bool
+	err := d.db.QueryRow(ctx, query,
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt,
+	).Scan(
+		&upserted.ID,
+		&upserted.Name,
+		&upserted.Email,
+		&upserted.Active,
+		&upserted.FavColor,
+		&upserted.FavNumbers,
+		&upserted.Properties,
+		&upserted.CreatedAt,
+		&inserted,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted, inserted, nil
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// COPY has no parameter limit, and pgx encodes each column using the
+	// types it reads from the table, so no wrappers or chunking are needed.
+	return d.db.CopyFrom(ctx,
+		pgx.Identifier{"accounts"},
+		[]string{
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at",
+		},
+		pgx.CopyFromSlice(len(accounts), func(i int) ([]any, error) {
+			return []any{
+				accounts[i].Name,
+				accounts[i].Email,
+				accounts[i].Active,
+				accounts[i].FavColor,
+				accounts[i].FavNumbers,
+				accounts[i].Properties,
+				accounts[i].CreatedAt,
+			}, nil
+		}),
+	)
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// A flat join repeats each account once per tag, and the LEFT JOINs keep
+	// the accounts without any tags as a single row of NULL tag columns
+	const query = `
+		SELECT
+			accounts.id,
+			accounts.name,
+			accounts.email,
+			accounts.active,
+			accounts.fav_color,
+			accounts.fav_numbers,
+			accounts.properties,
+			accounts.created_at,
+			tags.id,
+			tags.name
+		FROM accounts
+		LEFT JOIN account_tags ON account_tags.account_id = accounts.id
+		LEFT JOIN tags ON tags.id = account_tags.tag_id
+		ORDER BY accounts.id, tags.name`
+
+	rows, err := d.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// The rows are ordered by account, so each account's rows are consecutive,
+	// and can be folded into the last account
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		var tagID *uint64
+		var tagName *string
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&tagID,
+			&tagName)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		if len(accounts) == 0 || accounts[len(accounts)-1].ID != account.ID {
+			accounts = append(accounts, account)
+		}
+		if tagID != nil {
+			last := &accounts[len(accounts)-1]
+			last.Tags = append(last.Tags, models.Tag{ID: *tagID, Name: *tagName})
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, models.AccountBulkInserter, and
+// models.AccountTagSelector.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+	_ models.AccountTagSelector  = DAO{}
+)
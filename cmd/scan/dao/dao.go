@@ -0,0 +1,389 @@
+/*
+Build and run some queries using the scan library.
+Scan is similar to SQLX in that it just scans the results into structs/slices.
+Unlike SQLX, it operates on the 'rows' returned by the query, and therefore
+it has a much smaller api surface area.
+Because scan is only concerned with querying and scanning, it can be combined
+with a query builder library that provides the actual query strings.
+Unfortunately, scan does not work with PGX, so it has to scan into
+models.AccountCompatible, which is then converted to models.AccountIdeal.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	var account models.AccountCompatible
+	rows, err := d.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	err = scan.Row(&account, rows)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	var accounts []models.AccountCompatible
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scan.Rows(&accounts, rows)
+	return models.CompatibleToIdeal(accounts), err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	var accounts []models.AccountCompatible
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = scan.Rows(&accounts, rows); err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(models.CompatibleToIdeal(accounts))
+	return ideals, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			a.id,
+			a.name,
+			a.email,
+			a.active,
+			a.fav_color,
+			a.fav_numbers,
+			a.properties,
+			a.created_at,
+			o.id AS order_id,
+			o.account_id AS order_account_id,
+			o.product AS order_product,
+			o.quantity AS order_quantity,
+			o.price_cents AS order_price_cents,
+			o.created_at AS order_created_at
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	// Scan flattens nested structs without any prefix, so the order columns
+	// need names that do not collide with the account columns
+	var accountOrders []struct {
+		models.AccountCompatible
+		OrderID         *uint64    `db:"order_id"`
+		OrderAccountID  *uint64    `db:"order_account_id"`
+		OrderProduct    *string    `db:"order_product"`
+		OrderQuantity   *int       `db:"order_quantity"`
+		OrderPriceCents *int64     `db:"order_price_cents"`
+		OrderCreatedAt  *time.Time `db:"order_created_at"`
+	}
+	rows, err := d.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	if err = scan.Rows(&accountOrders, rows); err != nil || len(accountOrders) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := accountOrders[0].Ideal()
+	for _, row := range accountOrders {
+		order := models.NullOrder{
+			ID:         row.OrderID,
+			AccountID:  row.OrderAccountID,
+			Product:    row.OrderProduct,
+			Quantity:   row.OrderQuantity,
+			PriceCents: row.OrderPriceCents,
+			CreatedAt:  row.OrderCreatedAt,
+		}
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`
+
+	// No wrapper is needed for inserting a slice into a postgres array,
+	// because the pgx driver accepts any type that pgx itself can encode.
+	rows, err := d.db.QueryContext(ctx, query,
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+
+	var created models.AccountCompatible
+	if err = scan.Row(&created, rows); err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.Ideal(), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Sadly, we have to manually build dynamic updates too
+	var sets []string
+	var args []any
+	argCount := 1
+	if patch.Name != nil {
+		sets = append(sets, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *patch.Name)
+		argCount++
+	}
+	if patch.Email != nil {
+		sets = append(sets, fmt.Sprintf("email = $%d", argCount))
+		args = append(args, *patch.Email)
+		argCount++
+	}
+	if patch.Active != nil {
+		sets = append(sets, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *patch.Active)
+		argCount++
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, fmt.Sprintf("fav_color = $%d", argCount))
+		args = append(args, *patch.FavColor)
+		argCount++
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, fmt.Sprintf("fav_numbers = $%d", argCount))
+		args = append(args, *patch.FavNumbers)
+		argCount++
+	}
+	if patch.Properties != nil {
+		sets = append(sets, fmt.Sprintf("properties = $%d", argCount))
+		args = append(args, *patch.Properties)
+		argCount++
+	}
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`, strings.Join(sets, ", "), argCount)
+	models.LogQuery(query, args)
+
+	var account models.AccountCompatible
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	err = scan.Row(&account, rows)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// json_agg aggregates each account's tags into a json array, which is NULL
+	// for an account without any tags
+	const query = `
+		SELECT
+			accounts.id,
+			accounts.name,
+			accounts.email,
+			accounts.active,
+			accounts.fav_color,
+			accounts.fav_numbers,
+			accounts.properties,
+			accounts.created_at,
+			(
+				SELECT json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name)
+				FROM account_tags
+				JOIN tags ON tags.id = account_tags.tag_id
+				WHERE account_tags.account_id = accounts.id
+			) AS tags
+		FROM accounts
+		ORDER BY accounts.id`
+
+	// database/sql only hands the json array over as bytes, so it needs the
+	// models.Tags wrapper to unmarshal it
+	var accountTags []struct {
+		models.AccountCompatible
+		Tags models.Tags `db:"tags"`
+	}
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = scan.Rows(&accountTags, rows); err != nil || accountTags == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(accountTags))
+	for _, row := range accountTags {
+		account := row.Ideal()
+		account.Tags = row.Tags
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db models.SQLQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository and
+// models.AccountTagSelector.
+// It can run against either a *sql.DB or a *sql.Tx.
+func New(db models.SQLQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository  = DAO{}
+	_ models.AccountTagSelector = DAO{}
+)
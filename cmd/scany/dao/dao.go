@@ -0,0 +1,339 @@
+/*
+Build and run some queries using the Scany library.
+Scany is a better replacement of SQLX. It is focused just on running a query and
+scanning the results into structs or slices of structs.
+It is much more flexible than SQLX while providing a much smaller API surface
+area, which makes learning it easier.
+It does not embed the database connection, and because of that it can work with
+both database/sql and also PGX.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	var account models.AccountIdeal
+	err := pgxscan.Get(ctx, d.db, &account, query, id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	var accounts []models.AccountIdeal
+	err := pgxscan.Select(ctx, d.db, &accounts, query)
+	return accounts, err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	var accounts []models.AccountIdeal
+	if err = pgxscan.Select(ctx, d.db, &accounts, query, args...); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// scany maps the columns of nested structs by their prefix, such as "order.id"
+	const query = `
+		SELECT
+			a.id,
+			a.name,
+			a.email,
+			a.active,
+			a.fav_color,
+			a.fav_numbers,
+			a.properties,
+			a.created_at,
+			o.id AS "order.id",
+			o.account_id AS "order.account_id",
+			o.product AS "order.product",
+			o.quantity AS "order.quantity",
+			o.price_cents AS "order.price_cents",
+			o.created_at AS "order.created_at"
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	var rows []struct {
+		models.AccountIdeal
+		Order models.NullOrder `db:"order"`
+	}
+	if err := pgxscan.Select(ctx, d.db, &rows, query, id); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	if len(rows) == 0 {
+		return models.AccountIdeal{}, false, nil
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].AccountIdeal
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`
+
+	var created models.AccountIdeal
+	err := pgxscan.Get(ctx, d.db, &created, query,
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Sadly, we have to manually build dynamic updates too
+	var sets []string
+	var args []any
+	argCount := 1
+	if patch.Name != nil {
+		sets = append(sets, fmt.Sprintf("name = $%d", argCount))
+		args = append(args, *patch.Name)
+		argCount++
+	}
+	if patch.Email != nil {
+		sets = append(sets, fmt.Sprintf("email = $%d", argCount))
+		args = append(args, *patch.Email)
+		argCount++
+	}
+	if patch.Active != nil {
+		sets = append(sets, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *patch.Active)
+		argCount++
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, fmt.Sprintf("fav_color = $%d", argCount))
+		args = append(args, *patch.FavColor)
+		argCount++
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, fmt.Sprintf("fav_numbers = $%d", argCount))
+		args = append(args, *patch.FavNumbers)
+		argCount++
+	}
+	if patch.Properties != nil {
+		sets = append(sets, fmt.Sprintf("properties = $%d", argCount))
+		args = append(args, *patch.Properties)
+		argCount++
+	}
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE accounts
+		SET %s
+		WHERE id = $%d
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`, strings.Join(sets, ", "), argCount)
+	models.LogQuery(query, args)
+
+	var account models.AccountIdeal
+	err := pgxscan.Get(ctx, d.db, &account, query, args...)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// json_agg aggregates each account's tags into a json array, which is NULL
+	// for an account without any tags
+	const query = `
+		SELECT
+			accounts.id,
+			accounts.name,
+			accounts.email,
+			accounts.active,
+			accounts.fav_color,
+			accounts.fav_numbers,
+			accounts.properties,
+			accounts.created_at,
+			(
+				SELECT json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name)
+				FROM account_tags
+				JOIN tags ON tags.id = account_tags.tag_id
+				WHERE account_tags.account_id = accounts.id
+			) AS tags
+		FROM accounts
+		ORDER BY accounts.id`
+
+	// pgx unmarshals a json column into any type, so scany can scan the json
+	// array straight into []models.Tag
+	var rows []struct {
+		models.AccountIdeal
+		Tags []models.Tag `db:"tags"`
+	}
+	if err := pgxscan.Select(ctx, d.db, &rows, query); err != nil || rows == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(rows))
+	for _, row := range rows {
+		row.AccountIdeal.Tags = row.Tags
+		accounts = append(accounts, row.AccountIdeal)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository and
+// models.AccountTagSelector.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository  = DAO{}
+	_ models.AccountTagSelector = DAO{}
+)
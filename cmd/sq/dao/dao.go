@@ -0,0 +1,317 @@
+/*
+Build and run some queries using the sq and sqddl libraries.
+SQ is supposed to be a type-safe SQL builder, that also generates table
+definitions of your database schema.
+The way it scans structs is also very cumbersome and verbose.
+It does not do dynamic queries.
+It does not work with PGX.
+Slices passed as args are expanded into a list of args, so filtering has to use
+IN rather than ANY, and inserting a slice into an array column requires the
+sq.ArrayValue wrapper.
+There is no method to scan a nullable enum, and ArrayField scans an empty array
+into a nil slice, so those columns are scanned with ScanField and a sql.Scanner.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/bokwoon95/sq"
+	"github.com/veqryn/awesome-go-sql/cmd/sq/internal/table"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	account, err := sq.FetchOneContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.Queryf(
+			`SELECT {*}
+		FROM accounts
+		WHERE id = {}`,
+			id).SetDialect(sq.DialectPostgres),
+		accountFromRow,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// accountFromRow manually sets the scan column names.
+// There is no nullable enum method, and Array scans '{}' into a nil slice, so
+// the color and numbers are scanned with their sql.Scanner instead.
+// The color is scanned into a sql.Null rather than a *models.Color, because
+// VerboseLog calls Value on the scanned values, which panics on a nil pointer.
+func accountFromRow(row *sq.Row) models.AccountIdeal {
+	rval := models.AccountIdeal{
+		ID:        uint64(row.Int64("id")),
+		Name:      row.String("name"),
+		Email:     row.String("email"),
+		Active:    row.Bool("active"),
+		CreatedAt: row.Time("created_at"),
+	}
+	var favColor sql.Null[models.Color]
+	var favNumbers models.Array[int]
+	row.Scan(&favColor, "fav_color")
+	row.Scan(&favNumbers, "fav_numbers")
+	row.JSON(&rval.Properties, "properties")
+	if favColor.Valid {
+		rval.FavColor = &favColor.V
+	}
+	rval.FavNumbers = favNumbers.Get()
+	return rval
+}
+
+// accountFromFields uses the generated table definition to set the column
+// names, with the same workarounds as accountFromRow
+func accountFromFields(row *sq.Row, a table.ACCOUNTS) models.AccountIdeal {
+	rval := models.AccountIdeal{
+		ID:        uint64(row.Int64Field(a.ID)),
+		Name:      row.StringField(a.NAME),
+		Email:     row.StringField(a.EMAIL),
+		Active:    row.BoolField(a.ACTIVE),
+		CreatedAt: row.TimeField(a.CREATED_AT),
+	}
+	var favColor sql.Null[models.Color]
+	var favNumbers models.Array[int]
+	row.ScanField(&favColor, a.FAV_COLOR)
+	row.ScanField(&favNumbers, a.FAV_NUMBERS)
+	row.JSONField(&rval.Properties, a.PROPERTIES)
+	if favColor.Valid {
+		rval.FavColor = &favColor.V
+	}
+	rval.FavNumbers = favNumbers.Get()
+	return rval
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	// Use the generated table definition to set the column names
+	a := sq.New[table.ACCOUNTS]("accounts")
+	return sq.FetchAllContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.From(a).OrderBy(a.ID).SetDialect(sq.DialectPostgres),
+		func(row *sq.Row) models.AccountIdeal {
+			return accountFromFields(row, a)
+		})
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT {*}
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	if len(filters.Names) > 0 {
+		// Slices are expanded into a list of args, so ANY would not work
+		wheres = append(wheres, "name IN ({})")
+		args = append(args, filters.Names)
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, "active = {}")
+		args = append(args, *filters.Active)
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, "fav_color IN ({})")
+		args = append(args, filters.FavColors)
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, "(created_at, id) > ({}, {})")
+		args = append(args, keyset.CreatedAt, keyset.ID)
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += " LIMIT {}"
+		args = append(args, limit)
+	}
+
+	models.LogQuery(query, args)
+
+	// Use the generated table definition to set the column names
+	a := sq.New[table.ACCOUNTS]("accounts")
+	accounts, err := sq.FetchAllContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.Queryf(query, args...).SetDialect(sq.DialectPostgres),
+		func(row *sq.Row) models.AccountIdeal {
+			return accountFromFields(row, a)
+		})
+	if err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// Use the generated table definitions to set the column names
+	a := sq.New[table.ACCOUNTS]("accounts")
+	o := sq.New[table.ORDERS]("orders")
+
+	// The row mapper is called once up front to find the columns, so every
+	// field has to be read unconditionally, even the NULLs of the LEFT JOIN
+	type accountOrder struct {
+		account models.AccountIdeal
+		order   models.Order
+		ok      bool
+	}
+	rows, err := sq.FetchAllContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.From(a).
+			LeftJoin(o, o.ACCOUNT_ID.Eq(a.ID)).
+			Where(a.ID.EqInt64(int64(id))).
+			OrderBy(o.ID).
+			SetDialect(sq.DialectPostgres),
+		func(row *sq.Row) accountOrder {
+			rval := accountOrder{account: accountFromFields(row, a)}
+
+			orderID := row.NullInt64Field(o.ID)
+			rval.ok = orderID.Valid
+			rval.order = models.Order{
+				ID:         uint64(orderID.Int64),
+				AccountID:  uint64(row.Int64Field(o.ACCOUNT_ID)),
+				Product:    row.StringField(o.PRODUCT),
+				Quantity:   row.IntField(o.QUANTITY),
+				PriceCents: row.Int64Field(o.PRICE_CENTS),
+				CreatedAt:  row.TimeField(o.CREATED_AT),
+			}
+			return rval
+		})
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].account
+	for _, row := range rows {
+		if row.ok {
+			account.Orders = append(account.Orders, row.order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	// Slices are expanded into a list of args, so arrays need a wrapper
+	var favNumbers any
+	if account.FavNumbers != nil {
+		favNumbers = sq.ArrayValue(account.FavNumbers)
+	}
+	// sq calls Value on every driver.Valuer, which panics on a nil pointer
+	var favColor any
+	if account.FavColor != nil {
+		favColor = *account.FavColor
+	}
+
+	return sq.FetchOneContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.Queryf(
+			`INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+		VALUES ({}, {}, {}, {}, {}, {}, {})
+		RETURNING {*}`,
+			account.Name,
+			account.Email,
+			account.Active,
+			favColor,
+			favNumbers,
+			account.Properties,
+			account.CreatedAt).SetDialect(sq.DialectPostgres),
+		accountFromRow,
+	)
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Sadly, we have to manually build dynamic updates too
+	var sets []string
+	var args []any
+	if patch.Name != nil {
+		sets = append(sets, "name = {}")
+		args = append(args, *patch.Name)
+	}
+	if patch.Email != nil {
+		sets = append(sets, "email = {}")
+		args = append(args, *patch.Email)
+	}
+	if patch.Active != nil {
+		sets = append(sets, "active = {}")
+		args = append(args, *patch.Active)
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, "fav_color = {}")
+		if *patch.FavColor == nil {
+			// sq calls Value on every driver.Valuer, which panics on a nil pointer
+			args = append(args, nil)
+		} else {
+			args = append(args, **patch.FavColor)
+		}
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, "fav_numbers = {}")
+		if *patch.FavNumbers == nil {
+			args = append(args, nil)
+		} else {
+			// Slices are expanded into a list of args, so arrays need a wrapper
+			args = append(args, sq.ArrayValue(*patch.FavNumbers))
+		}
+	}
+	if patch.Properties != nil {
+		sets = append(sets, "properties = {}")
+		args = append(args, *patch.Properties)
+	}
+	args = append(args, id)
+
+	query := `
+		UPDATE accounts
+		SET ` + strings.Join(sets, ", ") + `
+		WHERE id = {}
+		RETURNING {*}`
+
+	models.LogQuery(query, args)
+
+	account, err := sq.FetchOneContext(ctx, sq.VerboseLog(d.db), // d.db,
+		sq.Queryf(query, args...).SetDialect(sq.DialectPostgres),
+		accountFromRow,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+type DAO struct {
+	db models.SQLQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository.
+// It can run against either a *sql.DB or a *sql.Tx.
+func New(db models.SQLQuerier) DAO {
+	return DAO{db: db}
+}
+
+var _ models.AccountRepository = DAO{}
@@ -13,3 +13,25 @@ type ACCOUNTS struct {
 	PROPERTIES  sq.JSONField    `ddl:"type=jsonb"`
 	CREATED_AT  sq.TimeField    `ddl:"type=timestamptz notnull"`
 }
+
+type ORDERS struct {
+	sq.TableStruct
+	ID          sq.NumberField `ddl:"type=bigint notnull primarykey default=nextval('orders_id_seq'::regclass)"`
+	ACCOUNT_ID  sq.NumberField `ddl:"type=bigint notnull references={accounts.id ondelete=cascade} index"`
+	PRODUCT     sq.StringField `ddl:"type=varchar(50) notnull"`
+	QUANTITY    sq.NumberField `ddl:"type=integer notnull"`
+	PRICE_CENTS sq.NumberField `ddl:"type=bigint notnull"`
+	CREATED_AT  sq.TimeField   `ddl:"type=timestamptz notnull"`
+}
+
+type TAGS struct {
+	sq.TableStruct
+	ID   sq.NumberField `ddl:"type=bigint notnull primarykey default=nextval('tags_id_seq'::regclass)"`
+	NAME sq.StringField `ddl:"type=varchar(50) notnull unique"`
+}
+
+type ACCOUNT_TAGS struct {
+	sq.TableStruct `ddl:"primarykey=account_id,tag_id"`
+	ACCOUNT_ID     sq.NumberField `ddl:"type=bigint notnull references={accounts.id ondelete=cascade}"`
+	TAG_ID         sq.NumberField `ddl:"type=bigint notnull references={tags.id ondelete=cascade} index"`
+}
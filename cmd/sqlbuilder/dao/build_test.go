@@ -0,0 +1,22 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	// The builder can not return an error, except when decoding the cursor
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID: func(id uint64) (string, []any, error) {
+			sqlStr, args := selectAccountByIDQuery(id)
+			return sqlStr, args, nil
+		},
+		SelectAllAccounts: func() (string, []any, error) {
+			sqlStr, args := selectAllAccountsQuery()
+			return sqlStr, args, nil
+		},
+		SelectAllAccountsByFilter: selectAllAccountsByFilterQuery,
+	})
+}
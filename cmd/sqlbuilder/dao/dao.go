@@ -0,0 +1,478 @@
+/*
+Build and run some queries using the sqlbuilder library.
+SQLBuilder is focused on flexibly building sql statements, and also has a very
+light struct-to-column-names introspection utility, that can be used to avoid
+writing out and potentially misspelling or not matching column names in queries.
+A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+for IN queries using a slice, and instead enumerates all values in the slice.
+It can be used to build dynamic queries, as well as non-standard sql.
+It can be combined with other libraries that do struct scanning or querying.
+It works with both database/sql and pgx.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args := selectAccountByIDQuery(id)
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// selectAccountByIDQuery builds the SQL separately from running it, so that
+// building can be benchmarked on its own.
+func selectAccountByIDQuery(id uint64) (string, []any) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	query := sb.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts").
+		Where(sb.EQ("id", id))
+
+	return query.Build()
+}
+
+// sqlbuilder.NewStruct() provides a way to generate the selected columns based
+// on a struct and its tags.
+var accountModel = sqlbuilder.NewStruct(models.AccountIdeal{}).For(sqlbuilder.PostgreSQL)
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	sqlStr, args := selectAllAccountsQuery()
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func selectAllAccountsQuery() (string, []any) {
+	// This generates the selected column names automatically based on the
+	// struct type definition.
+	query := accountModel.SelectFrom("accounts").OrderBy("id")
+
+	return query.Build()
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, args)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, "", scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	query := sb.Select(
+		"id",
+		"name",
+		"email",
+		"active",
+		"fav_color",
+		"fav_numbers",
+		"properties",
+		"created_at").
+		From("accounts")
+
+	// Nicely add filters dynamically
+	if len(filters.Names) > 0 {
+		query = query.Where(sb.In("name", sqlbuilder.List(filters.Names)))
+	}
+	if filters.Active != nil {
+		query = query.Where(sb.EQ("active", *filters.Active))
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		query = query.Where(sb.In("fav_color", sqlbuilder.List(filters.FavColors)))
+	}
+
+	// Continue after the cursor, if there is one
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		// No row value comparison, but Var adds the placeholders
+		query = query.Where(fmt.Sprintf("(created_at, id) > (%s, %s)", sb.Var(keyset.CreatedAt), sb.Var(keyset.ID)))
+	}
+
+	// Its Asc and Desc apply to every column, so each column's direction is
+	// part of the raw sql instead, which is only safe because the sort columns
+	// are whitelisted
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sort := range sorts {
+		query = query.OrderBy(sort.String())
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	sqlStr, args := query.Build()
+	return sqlStr, args, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args := selectAccountWithOrdersQuery(id)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	defer rows.Close()
+
+	// Every row repeats the account columns, with the columns of one order,
+	// so the rows have to be folded into a single account by hand
+	var account models.AccountIdeal
+	var found bool
+	for rows.Next() {
+		var order models.NullOrder
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&order.ID, // The LEFT JOIN makes every order column nullable
+			&order.AccountID,
+			&order.Product,
+			&order.Quantity,
+			&order.PriceCents,
+			&order.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return models.AccountIdeal{}, false, scanErr
+		}
+		found = true
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return account, found, nil
+}
+
+func selectAccountWithOrdersQuery(id uint64) (string, []any) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	query := sb.Select(
+		"a.id",
+		"a.name",
+		"a.email",
+		"a.active",
+		"a.fav_color",
+		"a.fav_numbers",
+		"a.properties",
+		"a.created_at",
+		"o.id",
+		"o.account_id",
+		"o.product",
+		"o.quantity",
+		"o.price_cents",
+		"o.created_at").
+		From(sb.As("accounts", "a")).
+		JoinWithOption(sqlbuilder.LeftJoin, sb.As("orders", "o"), "o.account_id = a.id").
+		Where(sb.EQ("a.id", id)).
+		OrderBy("o.id")
+
+	return query.Build()
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	sqlStr, args := createAccountQuery(account)
+
+	var created models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.Active,
+		&created.FavColor,
+		&created.FavNumbers,
+		&created.Properties,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func createAccountQuery(account models.AccountIdeal) (string, []any) {
+	ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+	ib.InsertInto("accounts")
+	ib.Cols("name", "email", "active", "fav_color", "fav_numbers", "properties", "created_at")
+	// Slices are only expanded when wrapped with sqlbuilder.List, so
+	// account.FavNumbers is passed as a single arg without a wrapper.
+	ib.Values(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt)
+	// There is no Returning method yet, so append it as raw SQL
+	ib.SQL("RETURNING " + strings.Join(accountModel.Columns(), ", "))
+
+	return ib.Build()
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sqlStr, args := updateAccountQuery(id, patch)
+	models.LogQuery(sqlStr, args)
+
+	var account models.AccountIdeal
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func updateAccountQuery(id uint64, patch models.AccountPatch) (string, []any) {
+	ub := sqlbuilder.PostgreSQL.NewUpdateBuilder()
+	ub.Update("accounts")
+
+	// Nicely add sets dynamically
+	if patch.Name != nil {
+		ub.SetMore(ub.Assign("name", *patch.Name))
+	}
+	if patch.Email != nil {
+		ub.SetMore(ub.Assign("email", *patch.Email))
+	}
+	if patch.Active != nil {
+		ub.SetMore(ub.Assign("active", *patch.Active))
+	}
+	if patch.FavColor != nil {
+		ub.SetMore(ub.Assign("fav_color", *patch.FavColor))
+	}
+	if patch.FavNumbers != nil {
+		ub.SetMore(ub.Assign("fav_numbers", *patch.FavNumbers))
+	}
+	if patch.Properties != nil {
+		ub.SetMore(ub.Assign("properties", *patch.Properties))
+	}
+
+	ub.Where(ub.Equal("id", id))
+	// There is no Returning method yet, so append it as raw SQL
+	ub.SQL("RETURNING " + strings.Join(accountModel.Columns(), ", "))
+
+	return ub.Build()
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	sqlStr, args := upsertAccountByEmailQuery(account)
+
+	var upserted models.AccountIdeal
+	var inserted bool
+	err := d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&upserted.ID,
+		&upserted.Name,
+		&upserted.Email,
+		&upserted.Active,
+		&upserted.FavColor,
+		&upserted.FavNumbers,
+		&upserted.Properties,
+		&upserted.CreatedAt,
+		&inserted,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted, inserted, nil
+}
+
+func upsertAccountByEmailQuery(account models.AccountIdeal) (string, []any) {
+	ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+	ib.InsertInto("accounts")
+	ib.Cols("name", "email", "active", "fav_color", "fav_numbers", "properties", "created_at")
+	ib.Values(
+		account.Name,
+		account.Email,
+		account.Active,
+		account.FavColor,
+		account.FavNumbers,
+		account.Properties,
+		account.CreatedAt)
+	// There is no OnConflict or Returning method yet, so append them as raw SQL.
+	// xmax is only zero if the row was inserted, not updated.
+	ib.SQL(`ON CONFLICT (email) DO UPDATE SET
+		name = EXCLUDED.name,
+		active = EXCLUDED.active,
+		fav_color = EXCLUDED.fav_color,
+		fav_numbers = EXCLUDED.fav_numbers,
+		properties = EXCLUDED.properties`)
+	ib.SQL("RETURNING " + strings.Join(accountModel.Columns(), ", ") + ", (xmax = 0) AS inserted")
+
+	return ib.Build()
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// Each account uses 7 bind parameters, so chunk to stay under the limit
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		sqlStr, args := bulkInsertAccountsQuery(chunk)
+
+		tag, err := d.db.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
+func bulkInsertAccountsQuery(accounts []models.AccountIdeal) (string, []any) {
+	ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+	ib.InsertInto("accounts")
+	ib.Cols("name", "email", "active", "fav_color", "fav_numbers", "properties", "created_at")
+	// Each call to Values adds another row
+	for _, account := range accounts {
+		ib.Values(
+			account.Name,
+			account.Email,
+			account.Active,
+			account.FavColor,
+			account.FavNumbers,
+			account.Properties,
+			account.CreatedAt)
+	}
+
+	return ib.Build()
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
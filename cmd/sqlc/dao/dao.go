@@ -0,0 +1,336 @@
+/*
+Build and run some queries using the SQLC generator library.
+SQLC is very different from the other libraries, in that it expects you to
+provide it both your schema (or database connection) and a file containing a
+list of queries you want to convert into code.
+It then generates both the struct models and the golang functions to match each
+query.
+It works wonderfully for any non-dynamic queries, but unfortunately it does not
+really cover dynamic queries.
+For dynamic queries, it can actually work if you use CASE statement in the WHERE,
+but this isn't something you'd want to do when there are more than 2-3 optional
+conditions, as it will create a very ugly query that probably won't get
+optimized well.
+You could choose to make use of the generated models with other libraries for
+dynamic SQL generation.
+Arrays of enum types, such as COLORS[], only work once the enum and its array
+type are registered on each pgx connection, see models.RegisterColors.
+SQLC works with both database/sql and PGX.
+The generated models have to be converted to models.AccountIdeal, see
+AccountToIdeal.
+Likewise, models.AccountIdeal has to be converted to the generated params in
+order to insert it.
+*/
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/veqryn/awesome-go-sql/cmd/sqlc/internal/model"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	account, err := d.queries.SelectAccountByID(ctx, int64(id))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		ideal, err := AccountToIdeal(account)
+		return ideal, err == nil, err
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	accounts, err := d.queries.SelectAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return AccountsToIdeal(accounts)
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	// The generated query uses a CASE statement per optional filter,
+	// so each filter needs a boolean saying whether to apply it.
+	params := model.SelectAllAccountsByFilterParams{
+		AnyNames: len(filters.Names) > 0,
+		Names:    filters.Names,
+		IsActive: filters.Active != nil,
+	}
+	if filters.Active != nil {
+		params.Active = *filters.Active
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		// pgx can only encode the []model.Colors as a COLORS[] if the pool
+		// registered the types in an AfterConnect hook, see models.RegisterColors
+		params.AnyFavColor = true
+		params.FavColors = make([]model.Colors, 0, len(filters.FavColors))
+		for _, color := range filters.FavColors {
+			params.FavColors = append(params.FavColors, model.Colors(color))
+		}
+	}
+	// A query can only have one ORDER BY, so only the default sort is generated
+	if _, err := filters.OrderBy(); err != nil {
+		return nil, "", err
+	}
+	if len(filters.Sort) > 0 {
+		return nil, "", fmt.Errorf("sqlc: sorting: %w", errors.ErrUnsupported)
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		params.HasCursor = true
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: keyset.CreatedAt, Valid: true}
+		params.CursorID = int64(keyset.ID)
+	}
+	if limit := filters.Limit(); limit > 0 {
+		params.PageLimit = pgtype.Int4{Int32: int32(limit), Valid: true}
+	}
+
+	accounts, err := d.queries.SelectAllAccountsByFilter(ctx, params)
+	if err != nil {
+		return nil, "", err
+	}
+	ideals, err := AccountsToIdeal(accounts)
+	if err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(ideals)
+	return ideals, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// sqlc.embed nests the account in each row, but the order columns are
+	// flattened, because a LEFT JOIN can not be embedded as a nullable struct
+	rows, err := d.queries.SelectAccountWithOrders(ctx, int64(id))
+	if err != nil || len(rows) == 0 {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account, err := AccountToIdeal(rows[0].Account)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	for _, row := range rows {
+		if !row.OrderID.Valid {
+			continue
+		}
+		account.Orders = append(account.Orders, models.Order{
+			ID:         uint64(row.OrderID.Int64),
+			AccountID:  uint64(row.OrderAccountID.Int64),
+			Product:    row.OrderProduct.String,
+			Quantity:   int(row.OrderQuantity.Int32),
+			PriceCents: row.OrderPriceCents.Int64,
+			CreatedAt:  row.OrderCreatedAt.Time,
+		})
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	created, err := d.queries.CreateAccount(ctx, createAccountParams(account))
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return AccountToIdeal(created)
+}
+
+// createAccountParams converts the account to the generated params, which use
+// the generated types
+func createAccountParams(account models.AccountIdeal) model.CreateAccountParams {
+	params := model.CreateAccountParams{
+		Name:      account.Name,
+		Email:     account.Email,
+		Active:    account.Active,
+		CreatedAt: pgtype.Timestamptz{Time: account.CreatedAt, Valid: true},
+	}
+	if account.FavColor != nil {
+		params.FavColor = model.NullColors{Colors: model.Colors(*account.FavColor), Valid: true}
+	}
+	if account.FavNumbers != nil {
+		params.FavNumbers = make([]int32, 0, len(account.FavNumbers))
+		for _, n := range account.FavNumbers {
+			params.FavNumbers = append(params.FavNumbers, int32(n))
+		}
+	}
+	params.Properties = propertiesToBytes(account.Properties)
+	return params
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// The generated query uses a CASE statement per optional column,
+	// so each column needs a boolean saying whether to set it.
+	params := model.UpdateAccountParams{
+		ID:            int64(id),
+		SetName:       patch.Name != nil,
+		SetEmail:      patch.Email != nil,
+		SetActive:     patch.Active != nil,
+		SetFavColor:   patch.FavColor != nil,
+		SetFavNumbers: patch.FavNumbers != nil,
+		SetProperties: patch.Properties != nil,
+	}
+	if patch.Name != nil {
+		params.Name = *patch.Name
+	}
+	if patch.Email != nil {
+		params.Email = *patch.Email
+	}
+	if patch.Active != nil {
+		params.Active = *patch.Active
+	}
+	if patch.FavColor != nil && *patch.FavColor != nil {
+		params.FavColor = model.NullColors{Colors: model.Colors(**patch.FavColor), Valid: true}
+	}
+	if patch.FavNumbers != nil && *patch.FavNumbers != nil {
+		params.FavNumbers = make([]int32, 0, len(*patch.FavNumbers))
+		for _, n := range *patch.FavNumbers {
+			params.FavNumbers = append(params.FavNumbers, int32(n))
+		}
+	}
+	if patch.Properties != nil {
+		params.Properties = propertiesToBytes(*patch.Properties)
+	}
+
+	account, err := d.queries.UpdateAccount(ctx, params)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		ideal, err := AccountToIdeal(account)
+		return ideal, err == nil, err
+	}
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	// The generated params are identical to CreateAccount's, so they convert
+	params := model.UpsertAccountByEmailParams(createAccountParams(account))
+
+	upserted, err := d.queries.UpsertAccountByEmail(ctx, params)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	// The generated row has the extra inserted column, so it is not an Account
+	ideal, err := AccountToIdeal(model.Account{
+		ID:         upserted.ID,
+		Name:       upserted.Name,
+		Email:      upserted.Email,
+		Active:     upserted.Active,
+		FavColor:   upserted.FavColor,
+		FavNumbers: upserted.FavNumbers,
+		Properties: upserted.Properties,
+		CreatedAt:  upserted.CreatedAt,
+	})
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return ideal, upserted.Inserted, nil
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// The generated :copyfrom params are identical to CreateAccount's
+	params := make([]model.CopyAccountsParams, 0, len(accounts))
+	for _, account := range accounts {
+		params = append(params, model.CopyAccountsParams(createAccountParams(account)))
+	}
+	return d.queries.CopyAccounts(ctx, params)
+}
+
+type DAO struct {
+	queries *model.Queries
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{queries: model.New(db)}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
+
+// AccountToIdeal converts the generated account model to an AccountIdeal
+func AccountToIdeal(a model.Account) (models.AccountIdeal, error) {
+	var favColor *models.Color
+	if a.FavColor.Valid {
+		color := models.Color(a.FavColor.Colors)
+		favColor = &color
+	}
+
+	var favNumbers []int
+	if a.FavNumbers != nil {
+		favNumbers = make([]int, 0, len(a.FavNumbers))
+		for _, n := range a.FavNumbers {
+			favNumbers = append(favNumbers, int(n))
+		}
+	}
+
+	// The generated model leaves jsonb as []byte, nil being NULL
+	var properties models.JSONB[models.AccountProperties]
+	if a.Properties != nil {
+		if err := properties.Scan(a.Properties); err != nil {
+			return models.AccountIdeal{}, err
+		}
+	}
+
+	return models.AccountIdeal{
+		ID:         uint64(a.ID),
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   favColor,
+		FavNumbers: favNumbers,
+		Properties: properties,
+		CreatedAt:  a.CreatedAt.Time,
+	}, nil
+}
+
+// propertiesToBytes converts the properties to the []byte the generated
+// params use for jsonb, returning nil for NULL
+func propertiesToBytes(properties models.JSONB[models.AccountProperties]) []byte {
+	if !properties.Valid {
+		return nil
+	}
+	// AccountProperties only holds json types, so marshalling can not fail
+	b, _ := json.Marshal(properties.V)
+	return b
+}
+
+// AccountsToIdeal converts a slice of the generated account model to AccountIdeal
+func AccountsToIdeal(accounts []model.Account) ([]models.AccountIdeal, error) {
+	if accounts == nil {
+		return nil, nil
+	}
+	ideals := make([]models.AccountIdeal, 0, len(accounts))
+	for _, account := range accounts {
+		ideal, err := AccountToIdeal(account)
+		if err != nil {
+			return nil, err
+		}
+		ideals = append(ideals, ideal)
+	}
+	return ideals, nil
+}
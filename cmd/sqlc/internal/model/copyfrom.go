@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: copyfrom.go
+
+package model
+
+import (
+	"context"
+)
+
+// iteratorForCopyAccounts implements pgx.CopyFromSource.
+type iteratorForCopyAccounts struct {
+	rows                 []CopyAccountsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyAccounts) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyAccounts) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].Name,
+		r.rows[0].Email,
+		r.rows[0].Active,
+		r.rows[0].FavColor,
+		r.rows[0].FavNumbers,
+		r.rows[0].Properties,
+		r.rows[0].CreatedAt,
+	}, nil
+}
+
+func (r iteratorForCopyAccounts) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyAccounts(ctx context.Context, arg []CopyAccountsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"accounts"}, []string{"name", "email", "active", "fav_color", "fav_numbers", "properties", "created_at"}, &iteratorForCopyAccounts{rows: arg})
+}
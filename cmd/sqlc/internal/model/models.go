@@ -64,3 +64,22 @@ type Account struct {
 	Properties []byte             `json:"properties"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
+
+type AccountTag struct {
+	AccountID int64 `json:"account_id"`
+	TagID     int64 `json:"tag_id"`
+}
+
+type Order struct {
+	ID         int64              `json:"id"`
+	AccountID  int64              `json:"account_id"`
+	Product    string             `json:"product"`
+	Quantity   int32              `json:"quantity"`
+	PriceCents int64              `json:"price_cents"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
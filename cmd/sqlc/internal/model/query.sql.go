@@ -7,8 +7,60 @@ package model
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyAccountsParams struct {
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Active     bool               `json:"active"`
+	FavColor   NullColors         `json:"fav_color"`
+	FavNumbers []int32            `json:"fav_numbers"`
+	Properties []byte             `json:"properties"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+const createAccount = `-- name: CreateAccount :one
+INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, name, email, active, fav_color, fav_numbers, properties, created_at
+`
+
+type CreateAccountParams struct {
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Active     bool               `json:"active"`
+	FavColor   NullColors         `json:"fav_color"`
+	FavNumbers []int32            `json:"fav_numbers"`
+	Properties []byte             `json:"properties"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.Name,
+		arg.Email,
+		arg.Active,
+		arg.FavColor,
+		arg.FavNumbers,
+		arg.Properties,
+		arg.CreatedAt,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Active,
+		&i.FavColor,
+		&i.FavNumbers,
+		&i.Properties,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const selectAccountByID = `-- name: SelectAccountByID :one
 SELECT id, name, email, active, fav_color, fav_numbers, properties, created_at
 FROM accounts
@@ -31,6 +83,66 @@ func (q *Queries) SelectAccountByID(ctx context.Context, id int64) (Account, err
 	return i, err
 }
 
+const selectAccountWithOrders = `-- name: SelectAccountWithOrders :many
+SELECT accounts.id, accounts.name, accounts.email, accounts.active, accounts.fav_color, accounts.fav_numbers, accounts.properties, accounts.created_at,
+       orders.id AS order_id,
+       orders.account_id AS order_account_id,
+       orders.product AS order_product,
+       orders.quantity AS order_quantity,
+       orders.price_cents AS order_price_cents,
+       orders.created_at AS order_created_at
+FROM accounts
+LEFT JOIN orders ON orders.account_id = accounts.id
+WHERE accounts.id = $1
+ORDER BY orders.id
+`
+
+type SelectAccountWithOrdersRow struct {
+	Account         Account            `json:"account"`
+	OrderID         pgtype.Int8        `json:"order_id"`
+	OrderAccountID  pgtype.Int8        `json:"order_account_id"`
+	OrderProduct    pgtype.Text        `json:"order_product"`
+	OrderQuantity   pgtype.Int4        `json:"order_quantity"`
+	OrderPriceCents pgtype.Int8        `json:"order_price_cents"`
+	OrderCreatedAt  pgtype.Timestamptz `json:"order_created_at"`
+}
+
+// The orders columns are nullable, because of the LEFT JOIN
+func (q *Queries) SelectAccountWithOrders(ctx context.Context, id int64) ([]SelectAccountWithOrdersRow, error) {
+	rows, err := q.db.Query(ctx, selectAccountWithOrders, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectAccountWithOrdersRow
+	for rows.Next() {
+		var i SelectAccountWithOrdersRow
+		if err := rows.Scan(
+			&i.Account.ID,
+			&i.Account.Name,
+			&i.Account.Email,
+			&i.Account.Active,
+			&i.Account.FavColor,
+			&i.Account.FavNumbers,
+			&i.Account.Properties,
+			&i.Account.CreatedAt,
+			&i.OrderID,
+			&i.OrderAccountID,
+			&i.OrderProduct,
+			&i.OrderQuantity,
+			&i.OrderPriceCents,
+			&i.OrderCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectAllAccounts = `-- name: SelectAllAccounts :many
 SELECT id, name, email, active, fav_color, fav_numbers, properties, created_at
 FROM accounts
@@ -72,17 +184,25 @@ FROM accounts
 WHERE (CASE WHEN $1::bool THEN name = ANY($2::text[]) ELSE TRUE END)
   AND (CASE WHEN $3::bool THEN active = $4 ELSE TRUE END)
   AND (CASE WHEN $5::bool THEN fav_color = ANY($6::COLORS[]) ELSE TRUE END)
+  AND (CASE WHEN $7::bool THEN (created_at, id) > ($8::timestamptz, $9::bigint) ELSE TRUE END)
+ORDER BY created_at, id
+LIMIT $10::int
 `
 
 type SelectAllAccountsByFilterParams struct {
-	AnyNames    bool     `json:"any_names"`
-	Names       []string `json:"names"`
-	IsActive    bool     `json:"is_active"`
-	Active      bool     `json:"active"`
-	AnyFavColor bool     `json:"any_fav_color"`
-	FavColors   []Colors `json:"fav_colors"`
+	AnyNames        bool               `json:"any_names"`
+	Names           []string           `json:"names"`
+	IsActive        bool               `json:"is_active"`
+	Active          bool               `json:"active"`
+	AnyFavColor     bool               `json:"any_fav_color"`
+	FavColors       []Colors           `json:"fav_colors"`
+	HasCursor       bool               `json:"has_cursor"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        int64              `json:"cursor_id"`
+	PageLimit       pgtype.Int4        `json:"page_limit"`
 }
 
+// page_limit is nullable, because LIMIT NULL is no limit
 func (q *Queries) SelectAllAccountsByFilter(ctx context.Context, arg SelectAllAccountsByFilterParams) ([]Account, error) {
 	rows, err := q.db.Query(ctx, selectAllAccountsByFilter,
 		arg.AnyNames,
@@ -91,6 +211,10 @@ func (q *Queries) SelectAllAccountsByFilter(ctx context.Context, arg SelectAllAc
 		arg.Active,
 		arg.AnyFavColor,
 		arg.FavColors,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -118,3 +242,122 @@ func (q *Queries) SelectAllAccountsByFilter(ctx context.Context, arg SelectAllAc
 	}
 	return items, nil
 }
+
+const updateAccount = `-- name: UpdateAccount :one
+UPDATE accounts
+SET name        = CASE WHEN $1::bool THEN $2::text ELSE name END,
+    email       = CASE WHEN $3::bool THEN $4::text ELSE email END,
+    active      = CASE WHEN $5::bool THEN $6::bool ELSE active END,
+    fav_color   = CASE WHEN $7::bool THEN $8::COLORS ELSE fav_color END,
+    fav_numbers = CASE WHEN $9::bool THEN $10::int[] ELSE fav_numbers END,
+    properties  = CASE WHEN $11::bool THEN $12::jsonb ELSE properties END
+WHERE id = $13
+RETURNING id, name, email, active, fav_color, fav_numbers, properties, created_at
+`
+
+type UpdateAccountParams struct {
+	SetName       bool       `json:"set_name"`
+	Name          string     `json:"name"`
+	SetEmail      bool       `json:"set_email"`
+	Email         string     `json:"email"`
+	SetActive     bool       `json:"set_active"`
+	Active        bool       `json:"active"`
+	SetFavColor   bool       `json:"set_fav_color"`
+	FavColor      NullColors `json:"fav_color"`
+	SetFavNumbers bool       `json:"set_fav_numbers"`
+	FavNumbers    []int32    `json:"fav_numbers"`
+	SetProperties bool       `json:"set_properties"`
+	Properties    []byte     `json:"properties"`
+	ID            int64      `json:"id"`
+}
+
+// fav_color is nullable so that an unset empty string is not cast to the enum
+func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccount,
+		arg.SetName,
+		arg.Name,
+		arg.SetEmail,
+		arg.Email,
+		arg.SetActive,
+		arg.Active,
+		arg.SetFavColor,
+		arg.FavColor,
+		arg.SetFavNumbers,
+		arg.FavNumbers,
+		arg.SetProperties,
+		arg.Properties,
+		arg.ID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Active,
+		&i.FavColor,
+		&i.FavNumbers,
+		&i.Properties,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertAccountByEmail = `-- name: UpsertAccountByEmail :one
+INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (email) DO UPDATE
+SET name        = EXCLUDED.name,
+    active      = EXCLUDED.active,
+    fav_color   = EXCLUDED.fav_color,
+    fav_numbers = EXCLUDED.fav_numbers,
+    properties  = EXCLUDED.properties
+RETURNING id, name, email, active, fav_color, fav_numbers, properties, created_at, (xmax = 0) AS inserted
+`
+
+type UpsertAccountByEmailParams struct {
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Active     bool               `json:"active"`
+	FavColor   NullColors         `json:"fav_color"`
+	FavNumbers []int32            `json:"fav_numbers"`
+	Properties []byte             `json:"properties"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type UpsertAccountByEmailRow struct {
+	ID         int64              `json:"id"`
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	Active     bool               `json:"active"`
+	FavColor   NullColors         `json:"fav_color"`
+	FavNumbers []int32            `json:"fav_numbers"`
+	Properties []byte             `json:"properties"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Inserted   bool               `json:"inserted"`
+}
+
+// xmax is only zero if the row was inserted, not updated
+func (q *Queries) UpsertAccountByEmail(ctx context.Context, arg UpsertAccountByEmailParams) (UpsertAccountByEmailRow, error) {
+	row := q.db.QueryRow(ctx, upsertAccountByEmail,
+		arg.Name,
+		arg.Email,
+		arg.Active,
+		arg.FavColor,
+		arg.FavNumbers,
+		arg.Properties,
+		arg.CreatedAt,
+	)
+	var i UpsertAccountByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Active,
+		&i.FavColor,
+		&i.FavNumbers,
+		&i.Properties,
+		&i.CreatedAt,
+		&i.Inserted,
+	)
+	return i, err
+}
@@ -9,9 +9,59 @@ FROM accounts
 ORDER BY id;
 
 -- name: SelectAllAccountsByFilter :many
+-- page_limit is nullable, because LIMIT NULL is no limit
 SELECT *
 FROM accounts
 WHERE (CASE WHEN @any_names::bool THEN name = ANY(@names::text[]) ELSE TRUE END)
   AND (CASE WHEN @is_active::bool THEN active = @active ELSE TRUE END)
   AND (CASE WHEN @any_fav_color::bool THEN fav_color = ANY(@fav_colors::COLORS[]) ELSE TRUE END)
-;
+  AND (CASE WHEN @has_cursor::bool THEN (created_at, id) > (@cursor_created_at::timestamptz, @cursor_id::bigint) ELSE TRUE END)
+ORDER BY created_at, id
+LIMIT sqlc.narg('page_limit')::int;
+
+-- name: CreateAccount :one
+INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: UpdateAccount :one
+-- fav_color is nullable so that an unset empty string is not cast to the enum
+UPDATE accounts
+SET name        = CASE WHEN @set_name::bool THEN @name::text ELSE name END,
+    email       = CASE WHEN @set_email::bool THEN @email::text ELSE email END,
+    active      = CASE WHEN @set_active::bool THEN @active::bool ELSE active END,
+    fav_color   = CASE WHEN @set_fav_color::bool THEN sqlc.narg('fav_color')::COLORS ELSE fav_color END,
+    fav_numbers = CASE WHEN @set_fav_numbers::bool THEN @fav_numbers::int[] ELSE fav_numbers END,
+    properties  = CASE WHEN @set_properties::bool THEN @properties::jsonb ELSE properties END
+WHERE id = @id
+RETURNING *;
+
+-- name: UpsertAccountByEmail :one
+-- xmax is only zero if the row was inserted, not updated
+INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (email) DO UPDATE
+SET name        = EXCLUDED.name,
+    active      = EXCLUDED.active,
+    fav_color   = EXCLUDED.fav_color,
+    fav_numbers = EXCLUDED.fav_numbers,
+    properties  = EXCLUDED.properties
+RETURNING *, (xmax = 0) AS inserted;
+
+-- name: CopyAccounts :copyfrom
+INSERT INTO accounts (name, email, active, fav_color, fav_numbers, properties, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: SelectAccountWithOrders :many
+-- The orders columns are nullable, because of the LEFT JOIN
+SELECT sqlc.embed(accounts),
+       orders.id AS order_id,
+       orders.account_id AS order_account_id,
+       orders.product AS order_product,
+       orders.quantity AS order_quantity,
+       orders.price_cents AS order_price_cents,
+       orders.created_at AS order_created_at
+FROM accounts
+LEFT JOIN orders ON orders.account_id = accounts.id
+WHERE accounts.id = $1
+ORDER BY orders.id;
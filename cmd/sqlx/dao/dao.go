@@ -0,0 +1,344 @@
+/*
+Build and run some queries using the SQLX library.
+The main advantage of this library is automatic scanning of rows into structs,
+or slices of structs.
+This cuts down on the boilerplate considerably.
+However, SQLX embeds the database/sql package's DB object, meaning that it does
+not work directly with PGX, and it also provides a very large api surface area.
+Because SQLX is only concerned with querying and scanning, it can be combined
+with a query builder library that provides the actual query strings.
+Because it goes through database/sql, it has to scan into
+models.AccountCompatible, which is then converted to models.AccountIdeal.
+Named queries also take their args from models.AccountCompatible.
+*/
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		WHERE id = $1`
+
+	var account models.AccountCompatible
+	err := sqlx.GetContext(ctx, d.db, &account, query, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	const query = `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts
+		ORDER BY id`
+
+	var accounts []models.AccountCompatible
+	err := sqlx.SelectContext(ctx, d.db, &accounts, query)
+	return models.CompatibleToIdeal(accounts), err
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		FROM accounts`
+
+	// Sadly, we have to manually build dynamic queries
+	var wheres []string
+	var args []any
+	argCount := 1
+	if len(filters.Names) > 0 {
+		wheres = append(wheres, fmt.Sprintf("name = ANY($%d)", argCount))
+		args = append(args, filters.Names)
+		argCount++
+	}
+	if filters.Active != nil {
+		wheres = append(wheres, fmt.Sprintf("active = $%d", argCount))
+		args = append(args, *filters.Active)
+		argCount++
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return nil, "", err
+		}
+		wheres = append(wheres, fmt.Sprintf("fav_color = ANY($%d)", argCount))
+		args = append(args, filters.FavColors)
+		argCount++
+	}
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return nil, "", err
+	}
+	if ok {
+		wheres = append(wheres, fmt.Sprintf("(created_at, id) > ($%d, $%d)", argCount, argCount+1))
+		args = append(args, keyset.CreatedAt, keyset.ID)
+		argCount += 2
+	}
+
+	if len(wheres) > 0 {
+		query += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	// The sort columns are whitelisted, so it is safe to add them to the query
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return nil, "", err
+	}
+	query += " ORDER BY " + models.OrderBySQL(sorts)
+	if limit := filters.Limit(); limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, limit)
+	}
+	models.LogQuery(query, args)
+
+	var accounts []models.AccountCompatible
+	if err = sqlx.SelectContext(ctx, d.db, &accounts, query, args...); err != nil {
+		return nil, "", err
+	}
+	ideals, next := filters.Page(models.CompatibleToIdeal(accounts))
+	return ideals, next, nil
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	// sqlx maps the columns of nested structs by their prefix, such as "order.id"
+	const query = `
+		SELECT
+			a.id,
+			a.name,
+			a.email,
+			a.active,
+			a.fav_color,
+			a.fav_numbers,
+			a.properties,
+			a.created_at,
+			o.id AS "order.id",
+			o.account_id AS "order.account_id",
+			o.product AS "order.product",
+			o.quantity AS "order.quantity",
+			o.price_cents AS "order.price_cents",
+			o.created_at AS "order.created_at"
+		FROM accounts a
+		LEFT JOIN orders o ON o.account_id = a.id
+		WHERE a.id = $1
+		ORDER BY o.id`
+
+	var rows []struct {
+		models.AccountCompatible
+		Order models.NullOrder `db:"order" json:"order"`
+	}
+	if err := sqlx.SelectContext(ctx, d.db, &rows, query, id); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	if len(rows) == 0 {
+		return models.AccountIdeal{}, false, nil
+	}
+
+	// Every row repeats the account, so only the orders are collected
+	account := rows[0].Ideal()
+	for _, row := range rows {
+		if order, ok := row.Order.Order(); ok {
+			account.Orders = append(account.Orders, order)
+		}
+	}
+	return account, true, nil
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	// Named queries take their args from the struct's fields
+	const query = `
+		INSERT INTO accounts (
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at
+		) VALUES (:name, :email, :active, :fav_color, :fav_numbers, :properties, :created_at)
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`
+
+	rows, err := sqlx.NamedQueryContext(ctx, d.db, query, account.Compatible())
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return models.AccountIdeal{}, err
+		}
+		return models.AccountIdeal{}, sql.ErrNoRows
+	}
+
+	var created models.AccountCompatible
+	if err = rows.StructScan(&created); err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created.Ideal(), nil
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	// Named args at least save us from counting the args
+	var sets []string
+	namedArgs := map[string]any{"id": id}
+	if patch.Name != nil {
+		sets = append(sets, "name = :name")
+		namedArgs["name"] = *patch.Name
+	}
+	if patch.Email != nil {
+		sets = append(sets, "email = :email")
+		namedArgs["email"] = *patch.Email
+	}
+	if patch.Active != nil {
+		sets = append(sets, "active = :active")
+		namedArgs["active"] = *patch.Active
+	}
+	if patch.FavColor != nil {
+		sets = append(sets, "fav_color = :fav_color")
+		namedArgs["fav_color"] = *patch.FavColor
+	}
+	if patch.FavNumbers != nil {
+		sets = append(sets, "fav_numbers = :fav_numbers")
+		namedArgs["fav_numbers"] = *patch.FavNumbers
+	}
+	if patch.Properties != nil {
+		sets = append(sets, "properties = :properties")
+		namedArgs["properties"] = *patch.Properties
+	}
+
+	query, args, err := d.db.BindNamed(`
+		UPDATE accounts
+		SET `+strings.Join(sets, ", ")+`
+		WHERE id = :id
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`, namedArgs)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	models.LogQuery(query, args)
+
+	var account models.AccountCompatible
+	err = sqlx.GetContext(ctx, d.db, &account, query, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.AccountIdeal{}, false, nil
+	case err != nil:
+		return models.AccountIdeal{}, false, err
+	default:
+		return account.Ideal(), true, nil
+	}
+}
+
+func (d DAO) SelectAllAccountsWithTags(ctx context.Context) ([]models.AccountIdeal, error) {
+	// json_agg aggregates each account's tags into a json array, which is NULL
+	// for an account without any tags
+	const query = `
+		SELECT
+			accounts.id,
+			accounts.name,
+			accounts.email,
+			accounts.active,
+			accounts.fav_color,
+			accounts.fav_numbers,
+			accounts.properties,
+			accounts.created_at,
+			(
+				SELECT json_agg(json_build_object('id', tags.id, 'name', tags.name) ORDER BY tags.name)
+				FROM account_tags
+				JOIN tags ON tags.id = account_tags.tag_id
+				WHERE account_tags.account_id = accounts.id
+			) AS tags
+		FROM accounts
+		ORDER BY accounts.id`
+
+	// database/sql only hands the json array over as bytes, so it needs the
+	// models.Tags wrapper to unmarshal it
+	var rows []struct {
+		models.AccountCompatible
+		Tags models.Tags `db:"tags" json:"tags"`
+	}
+	if err := sqlx.SelectContext(ctx, d.db, &rows, query); err != nil || rows == nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountIdeal, 0, len(rows))
+	for _, row := range rows {
+		account := row.Ideal()
+		account.Tags = row.Tags
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+type DAO struct {
+	db sqlx.ExtContext // Wrap the db connection
+}
+
+// New returns a DAO, which implements models.AccountRepository and
+// models.AccountTagSelector.
+// It can run against either a *sqlx.DB or a *sqlx.Tx.
+func New(db sqlx.ExtContext) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository  = DAO{}
+	_ models.AccountTagSelector = DAO{}
+)
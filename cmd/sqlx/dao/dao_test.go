@@ -0,0 +1,35 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/veqryn/awesome-go-sql/cmd/sqlx/dao"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, dao.New(sqlx.NewDb(conformance.DB(t), "pgx")))
+}
+
+func TestTx(t *testing.T) {
+	db := sqlx.NewDb(conformance.DB(t), "pgx")
+	conformance.RunTx(t, func(ctx context.Context, fn func(context.Context, models.AccountRepository) error) error {
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err = fn(ctx, dao.New(tx)); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func BenchmarkDAO(b *testing.B) {
+	conformance.Bench(b, dao.New(sqlx.NewDb(conformance.DB(b), "pgx")))
+}
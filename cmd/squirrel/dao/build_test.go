@@ -0,0 +1,15 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	conformance.BenchBuild(b, conformance.Builder{
+		SelectAccountByID:         selectAccountByIDQuery,
+		SelectAllAccounts:         selectAllAccountsQuery,
+		SelectAllAccountsByFilter: selectAllAccountsByFilterQuery,
+	})
+}
@@ -0,0 +1,540 @@
+/*
+Build and run some queries using the Squirrel library.
+The main advantage of this library is using it to build dynamic queries,
+though it can be used to build any queries.
+A very minor disadvantage is that it doesn't use postgres' =ANY($1) format
+for IN queries using a slice, and instead enumerates all values in the slice.
+Because it only creates the SQL string and argument list, it can be combined
+with other libraries that do struct scanning or querying.
+It works with both database/sql and pgx.
+*/
+package dao
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5" // DB Driver
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+func (d DAO) SelectAccountByID(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := selectAccountByIDQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	var account models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+// selectAccountByIDQuery builds the SQL separately from running it, so that
+// building can be benchmarked on its own.
+func selectAccountByIDQuery(id uint64) (string, []any, error) {
+	query := sq.
+		Select(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		From("accounts").
+		Where(sq.Eq{"id": id})
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) SelectAllAccounts(ctx context.Context) ([]models.AccountIdeal, error) {
+	sqlStr, args, err := selectAllAccountsQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func selectAllAccountsQuery() (string, []any, error) {
+	query := sq.
+		Select(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		From("accounts").
+		OrderBy("id")
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) SelectAllAccountsByFilter(ctx context.Context, filters models.Filters) ([]models.AccountIdeal, models.Cursor, error) {
+	sqlStr, args, err := selectAllAccountsByFilterQuery(filters)
+	if err != nil {
+		return nil, "", err
+	}
+	models.LogQuery(sqlStr, args)
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountIdeal
+	for rows.Next() {
+		var account models.AccountIdeal
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return nil, "", scanErr
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	accounts, next := filters.Page(accounts)
+	return accounts, next, nil
+}
+
+func selectAllAccountsByFilterQuery(filters models.Filters) (string, []any, error) {
+	query := sq.
+		Select(
+			"id",
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		From("accounts")
+
+	// Nicely add filters dynamically
+	if len(filters.Names) > 0 {
+		query = query.Where(sq.Eq{"name": filters.Names})
+	}
+	if filters.Active != nil {
+		query = query.Where(sq.Eq{"active": *filters.Active})
+	}
+	if len(filters.FavColors) > 0 {
+		if err := models.ValidateColors(filters.FavColors); err != nil {
+			return "", nil, err
+		}
+		query = query.Where(sq.Eq{"fav_color": filters.FavColors})
+	}
+
+	// Continue after the cursor, if there is one
+	keyset, ok, err := filters.Cursor.Keyset()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		query = query.Where(sq.Expr("(created_at, id) > (?, ?)", keyset.CreatedAt, keyset.ID))
+	}
+
+	// Squirrel takes the ORDER BY as raw sql, which is only safe because the
+	// sort columns are whitelisted
+	sorts, err := filters.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sort := range sorts {
+		query = query.OrderBy(sort.String())
+	}
+	if limit := filters.Limit(); limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) SelectAccountWithOrders(ctx context.Context, id uint64) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := selectAccountWithOrdersQuery(id)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	rows, err := d.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	defer rows.Close()
+
+	// Every row repeats the account columns, with the columns of one order,
+	// so the rows have to be folded into a single account by hand
+	var account models.AccountIdeal
+	var found bool
+	for rows.Next() {
+		var order models.NullOrder
+		scanErr := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Email,
+			&account.Active,
+			&account.FavColor,
+			&account.FavNumbers,
+			&account.Properties,
+			&account.CreatedAt,
+			&order.ID, // The LEFT JOIN makes every order column nullable
+			&order.AccountID,
+			&order.Product,
+			&order.Quantity,
+			&order.PriceCents,
+			&order.CreatedAt)
+		if scanErr != nil {
+			// Check for a scan error. Query rows will be closed with defer.
+			return models.AccountIdeal{}, false, scanErr
+		}
+		found = true
+		if o, ok := order.Order(); ok {
+			account.Orders = append(account.Orders, o)
+		}
+	}
+
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err = rows.Err(); err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return account, found, nil
+}
+
+func selectAccountWithOrdersQuery(id uint64) (string, []any, error) {
+	query := sq.
+		Select(
+			"a.id",
+			"a.name",
+			"a.email",
+			"a.active",
+			"a.fav_color",
+			"a.fav_numbers",
+			"a.properties",
+			"a.created_at",
+			"o.id",
+			"o.account_id",
+			"o.product",
+			"o.quantity",
+			"o.price_cents",
+			"o.created_at").
+		From("accounts a").
+		LeftJoin("orders o ON o.account_id = a.id").
+		Where(sq.Eq{"a.id": id}).
+		OrderBy("o.id")
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) CreateAccount(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, error) {
+	sqlStr, args, err := createAccountQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+
+	var created models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.Active,
+		&created.FavColor,
+		&created.FavNumbers,
+		&created.Properties,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, err
+	}
+	return created, nil
+}
+
+func createAccountQuery(account models.AccountIdeal) (string, []any, error) {
+	query := sq.
+		Insert("accounts").
+		Columns(
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		Values(
+			account.Name,
+			account.Email,
+			account.Active,
+			account.FavColor,
+			account.FavNumbers, // Slices are only expanded inside sq.Eq
+			account.Properties,
+			account.CreatedAt).
+		Suffix(`RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`)
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) UpdateAccount(ctx context.Context, id uint64, patch models.AccountPatch) (models.AccountIdeal, bool, error) {
+	if patch.IsEmpty() {
+		return d.SelectAccountByID(ctx, id)
+	}
+
+	sqlStr, args, err := updateAccountQuery(id, patch)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	models.LogQuery(sqlStr, args)
+
+	var account models.AccountIdeal
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.Active,
+		&account.FavColor,
+		&account.FavNumbers,
+		&account.Properties,
+		&account.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return account, false, nil
+	case err != nil:
+		return account, false, err
+	default:
+		return account, true, nil
+	}
+}
+
+func updateAccountQuery(id uint64, patch models.AccountPatch) (string, []any, error) {
+	query := sq.Update("accounts")
+
+	// Nicely add sets dynamically
+	if patch.Name != nil {
+		query = query.Set("name", *patch.Name)
+	}
+	if patch.Email != nil {
+		query = query.Set("email", *patch.Email)
+	}
+	if patch.Active != nil {
+		query = query.Set("active", *patch.Active)
+	}
+	if patch.FavColor != nil {
+		query = query.Set("fav_color", *patch.FavColor)
+	}
+	if patch.FavNumbers != nil {
+		query = query.Set("fav_numbers", *patch.FavNumbers)
+	}
+	if patch.Properties != nil {
+		query = query.Set("properties", *patch.Properties)
+	}
+
+	query = query.
+		Where(sq.Eq{"id": id}).
+		Suffix(`RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at`)
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) UpsertAccountByEmail(ctx context.Context, account models.AccountIdeal) (models.AccountIdeal, bool, error) {
+	sqlStr, args, err := upsertAccountByEmailQuery(account)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+
+	var upserted models.AccountIdeal
+	var inserted bool
+	err = d.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&upserted.ID,
+		&upserted.Name,
+		&upserted.Email,
+		&upserted.Active,
+		&upserted.FavColor,
+		&upserted.FavNumbers,
+		&upserted.Properties,
+		&upserted.CreatedAt,
+		&inserted,
+	)
+	if err != nil {
+		return models.AccountIdeal{}, false, err
+	}
+	return upserted, inserted, nil
+}
+
+func upsertAccountByEmailQuery(account models.AccountIdeal) (string, []any, error) {
+	query := sq.
+		Insert("accounts").
+		Columns(
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at").
+		Values(
+			account.Name,
+			account.Email,
+			account.Active,
+			account.FavColor,
+			account.FavNumbers, // Slices are only expanded inside sq.Eq
+			account.Properties,
+			account.CreatedAt).
+		// xmax is only zero if the row was inserted, not updated
+		Suffix(`ON CONFLICT (email) DO UPDATE SET
+			name = EXCLUDED.name,
+			active = EXCLUDED.active,
+			fav_color = EXCLUDED.fav_color,
+			fav_numbers = EXCLUDED.fav_numbers,
+			properties = EXCLUDED.properties
+		RETURNING
+			id,
+			name,
+			email,
+			active,
+			fav_color,
+			fav_numbers,
+			properties,
+			created_at,
+			(xmax = 0) AS inserted`)
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (d DAO) BulkInsertAccounts(ctx context.Context, accounts []models.AccountIdeal) (int64, error) {
+	// Each account uses 7 bind parameters, so chunk to stay under the limit
+	var total int64
+	for _, chunk := range models.Chunk(accounts, 7) {
+		sqlStr, args, err := bulkInsertAccountsQuery(chunk)
+		if err != nil {
+			return total, err
+		}
+
+		tag, err := d.db.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
+func bulkInsertAccountsQuery(accounts []models.AccountIdeal) (string, []any, error) {
+	query := sq.
+		Insert("accounts").
+		Columns(
+			"name",
+			"email",
+			"active",
+			"fav_color",
+			"fav_numbers",
+			"properties",
+			"created_at")
+
+	// Each call to Values adds another row
+	for _, account := range accounts {
+		query = query.Values(
+			account.Name,
+			account.Email,
+			account.Active,
+			account.FavColor,
+			account.FavNumbers, // Slices are only expanded inside sq.Eq
+			account.Properties,
+			account.CreatedAt)
+	}
+
+	return query.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+type DAO struct {
+	db models.PgxQuerier
+}
+
+// New returns a DAO, which implements models.AccountRepository,
+// models.AccountUpserter, and models.AccountBulkInserter.
+// It can run against either a *pgxpool.Pool or a pgx.Tx.
+func New(db models.PgxQuerier) DAO {
+	return DAO{db: db}
+}
+
+var (
+	_ models.AccountRepository   = DAO{}
+	_ models.AccountUpserter     = DAO{}
+	_ models.AccountBulkInserter = DAO{}
+)
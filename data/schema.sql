@@ -11,9 +11,62 @@ CREATE TABLE accounts (
     created_at  TIMESTAMP WITH TIME ZONE NOT NULL
 );
 
+-- Keyset pagination orders and seeks by (created_at, id)
+CREATE INDEX accounts_created_at_id_idx ON accounts (created_at, id);
+
+CREATE TABLE orders (
+    id          BIGSERIAL PRIMARY KEY,
+    account_id  BIGINT                   NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    product     VARCHAR(50)              NOT NULL,
+    quantity    INTEGER                  NOT NULL,
+    price_cents BIGINT                   NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Postgres does not index the referencing side of a foreign key by itself
+CREATE INDEX orders_account_id_idx ON orders (account_id);
+
+CREATE TABLE tags (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL
+);
+
+-- account_tags relates accounts and tags, many to many
+CREATE TABLE account_tags (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    tag_id     BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (account_id, tag_id)
+);
+
+-- The primary key already indexes account_id, but not tag_id on its own
+CREATE INDEX account_tags_tag_id_idx ON account_tags (tag_id);
+
 INSERT INTO accounts (id, name, email, active, fav_color, fav_numbers, properties, created_at)
 VALUES (1, 'Bob', 'bob@internal.com', true, 'red', '{5}', '{"tags": ["fun"]}', '2024-08-28T01:02:03Z'),
        (2, 'Jane', 'jane@internal.com', true, 'green', '{3, 19}', '{"tags": ["happy"]}', '2024-08-28T01:04:05Z'),
        (3, 'John', 'john@internal.com', false, null, '{}', '{}', '2024-08-28T01:06:07Z'),
        (4, 'Jack', 'jack@internal.com', false, null, null, null, NOW())
 ;
+
+INSERT INTO orders (id, account_id, product, quantity, price_cents, created_at)
+VALUES (1, 1, 'Widget', 2, 1999, '2024-08-29T10:00:00Z'),
+       (2, 1, 'Gadget', 1, 4999, '2024-08-30T11:30:00Z'),
+       (3, 2, 'Widget', 5, 1999, '2024-08-31T12:45:00Z')
+;
+
+INSERT INTO tags (id, name)
+VALUES (1, 'admin'),
+       (2, 'beta'),
+       (3, 'vip')
+;
+
+INSERT INTO account_tags (account_id, tag_id)
+VALUES (1, 3),
+       (1, 1),
+       (2, 2)
+;
+
+-- Inserting explicit ids does not advance the sequences
+SELECT setval('accounts_id_seq', (SELECT MAX(id) FROM accounts));
+SELECT setval('orders_id_seq', (SELECT MAX(id) FROM orders));
+SELECT setval('tags_id_seq', (SELECT MAX(id) FROM tags));
@@ -0,0 +1,125 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// BenchFilters is the same dynamic query that each example's main runs
+var BenchFilters = models.Filters{
+	Names:     []string{"Jane", "John"},
+	Active:    ptr(true),
+	FavColors: []models.Color{models.ColorRed, models.ColorBlue, models.ColorGreen},
+}
+
+// Bench benchmarks running and scanning each query of the repository.
+// Query logging is silenced while benchmarking.
+func Bench(b *testing.B, repo models.AccountRepository) {
+	silenceLogQuery(b)
+	ctx := context.Background()
+
+	b.Run("SelectAccountByID", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.SelectAccountByID(ctx, 2); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SelectAllAccounts", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.SelectAllAccounts(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SelectAllAccountsByFilter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.SelectAllAccountsByFilter(ctx, BenchFilters); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	if bulkInserter, ok := repo.(models.AccountBulkInserter); ok {
+		b.Run("BulkInsertAccounts", func(b *testing.B) {
+			benchBulkInsertAccounts(b, bulkInserter)
+		})
+	}
+}
+
+// BenchBulkSize is the number of accounts inserted by each iteration of the
+// BulkInsertAccounts benchmark
+const BenchBulkSize = 1000
+
+// benchBulkInsertAccounts compares COPY against multi-row INSERT ... VALUES,
+// with the array and jsonb columns set on every account
+func benchBulkInsertAccounts(b *testing.B, bulkInserter models.AccountBulkInserter) {
+	ctx := context.Background()
+	prefix := fmt.Sprintf("bench-%d-", time.Now().UnixNano())
+	deleteAccountsByEmail(b, prefix)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		accounts := BulkAccounts(fmt.Sprintf("%s%d-", prefix, i), BenchBulkSize)
+		b.StartTimer()
+
+		if _, err := bulkInserter.BulkInsertAccounts(ctx, accounts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Builder builds the SQL string and args of each query, without running them
+type Builder struct {
+	SelectAccountByID         func(id uint64) (string, []any, error)
+	SelectAllAccounts         func() (string, []any, error)
+	SelectAllAccountsByFilter func(filters models.Filters) (string, []any, error)
+}
+
+// BenchBuild benchmarks only building the SQL of each query, which does not
+// need a database.
+func BenchBuild(b *testing.B, builder Builder) {
+	b.Run("SelectAccountByID", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := builder.SelectAccountByID(2); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SelectAllAccounts", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := builder.SelectAllAccounts(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SelectAllAccountsByFilter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := builder.SelectAllAccountsByFilter(BenchFilters); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// silenceLogQuery disables models.LogQuery until the benchmark is done
+func silenceLogQuery(b *testing.B) {
+	logQuery := models.LogQuery
+	models.LogQuery = func(string, []any) {}
+	b.Cleanup(func() { models.LogQuery = logQuery })
+}
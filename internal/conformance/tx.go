@@ -0,0 +1,89 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/veqryn/awesome-go-sql/models"
+)
+
+// InTx runs fn in a transaction, with a repository constructed against that
+// transaction. The transaction must be rolled back if fn returns an error, and
+// that error returned.
+type InTx func(ctx context.Context, fn func(ctx context.Context, repo models.AccountRepository) error) error
+
+// errRollback is returned from inside the transaction to force a rollback
+var errRollback = errors.New("rollback")
+
+// RunTx checks that a repository constructed against a transaction reads its
+// own writes, and that those writes are only visible outside the transaction
+// once it is committed.
+func RunTx(t *testing.T, inTx InTx) {
+	t.Run("rollback", func(t *testing.T) {
+		var id uint64
+		err := inTx(context.Background(), func(ctx context.Context, repo models.AccountRepository) error {
+			id = createAndSelect(t, ctx, repo, "Tx Rollback")
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("expected the rollback error, got: %v", err)
+		}
+		if accountExists(t, id) {
+			t.Errorf("account %d should have been rolled back", id)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		var id uint64
+		err := inTx(context.Background(), func(ctx context.Context, repo models.AccountRepository) error {
+			id = createAndSelect(t, ctx, repo, "Tx Commit")
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !accountExists(t, id) {
+			t.Errorf("account %d should have been committed", id)
+		}
+	})
+}
+
+// createAndSelect creates an account inside the transaction, and checks that
+// the transaction can read it back
+func createAndSelect(t *testing.T, ctx context.Context, repo models.AccountRepository, name string) uint64 {
+	t.Helper()
+	created, err := repo.CreateAccount(ctx, models.AccountIdeal{
+		Name:      name,
+		Email:     fmt.Sprintf("tx-%d@test.com", time.Now().UnixNano()),
+		Active:    true,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleteAccount(t, created.ID)
+
+	got, ok, err := repo.SelectAccountByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("account %d not found inside its own transaction", created.ID)
+	}
+	assertAccount(t, created, got)
+	return created.ID
+}
+
+// accountExists checks for the account outside of any transaction
+func accountExists(t *testing.T, id uint64) bool {
+	t.Helper()
+	var exists bool
+	err := Pool(t).QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
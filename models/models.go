@@ -1,13 +1,21 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -15,32 +23,507 @@ import (
 type Filters struct {
 	Names     []string
 	Active    *bool
-	FavColors []string
+	FavColors []Color
+
+	// Sort overrides the default order of (created_at, id).
+	// It can not be combined with pagination, which relies on that order.
+	Sort []Sort
+
+	// PageSize is the maximum number of accounts to return, or 0 for all of them
+	PageSize int
+	// Cursor continues from the end of a previous page, empty for the first page
+	Cursor Cursor
+}
+
+// ErrInvalidSort is returned when a Sort is not in the whitelist
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortColumn is a column of the accounts table that can be sorted by
+type SortColumn string
+
+const (
+	SortByID        SortColumn = "id"
+	SortByName      SortColumn = "name"
+	SortByEmail     SortColumn = "email"
+	SortByActive    SortColumn = "active"
+	SortByFavColor  SortColumn = "fav_color"
+	SortByCreatedAt SortColumn = "created_at"
+)
+
+// Nulls is where NULLs are sorted, with the empty value using postgres'
+// default of last when ascending and first when descending
+type Nulls string
+
+const (
+	NullsFirst Nulls = "FIRST"
+	NullsLast  Nulls = "LAST"
+)
+
+// Sort is one column of a dynamic ORDER BY.
+// Sorts usually come from user input, so must be validated with
+// Filters.OrderBy before being used in a query.
+type Sort struct {
+	Column SortColumn
+	Desc   bool
+	Nulls  Nulls
+}
+
+// DefaultSort is the order that keyset pagination relies on
+var DefaultSort = []Sort{{Column: SortByCreatedAt}, {Column: SortByID}}
+
+// OrderBy returns a copy of the validated sort of the filters, or of
+// DefaultSort if none is set, so that callers can append to it.
+// It returns an error wrapping ErrInvalidSort if any column or nulls is not in
+// the whitelist, so that user input is never interpolated into the query.
+func (f Filters) OrderBy() ([]Sort, error) {
+	if len(f.Sort) == 0 {
+		return slices.Clone(DefaultSort), nil
+	}
+	if f.PageSize > 0 || f.Cursor != "" {
+		return nil, fmt.Errorf("%w: pagination only supports the default sort", ErrInvalidSort)
+	}
+	for _, sort := range f.Sort {
+		switch sort.Column {
+		case SortByID, SortByName, SortByEmail, SortByActive, SortByFavColor, SortByCreatedAt:
+		default:
+			return nil, fmt.Errorf("%w: column %q", ErrInvalidSort, sort.Column)
+		}
+		switch sort.Nulls {
+		case "", NullsFirst, NullsLast:
+		default:
+			return nil, fmt.Errorf("%w: nulls %q", ErrInvalidSort, sort.Nulls)
+		}
+	}
+	return slices.Clone(f.Sort), nil
+}
+
+// String returns the sort as SQL, such as "fav_color DESC NULLS LAST".
+// It must only be called on a Sort validated by Filters.OrderBy.
+func (s Sort) String() string {
+	sql := string(s.Column)
+	if s.Desc {
+		sql += " DESC"
+	}
+	if s.Nulls != "" {
+		sql += " NULLS " + string(s.Nulls)
+	}
+	return sql
+}
+
+// OrderBySQL joins the sorts into the SQL of an ORDER BY clause, for the
+// examples that build their queries by hand.
+// It must only be called with sorts validated by Filters.OrderBy.
+func OrderBySQL(sorts []Sort) string {
+	sql := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		sql = append(sql, sort.String())
+	}
+	return strings.Join(sql, ", ")
+}
+
+// Limit returns the LIMIT of a paginated query, or 0 if the filters are not
+// paginated.
+// It is one more than the page size, so that Page can tell if there is a next
+// page without running another query.
+func (f Filters) Limit() int {
+	if f.PageSize <= 0 {
+		return 0
+	}
+	return f.PageSize + 1
+}
+
+// Page trims the accounts queried with Limit down to the page size, and returns
+// the cursor of the next page, or an empty cursor if this is the last page.
+func (f Filters) Page(accounts []AccountIdeal) ([]AccountIdeal, Cursor) {
+	if f.PageSize <= 0 || len(accounts) <= f.PageSize {
+		return accounts, ""
+	}
+	accounts = accounts[:f.PageSize]
+	return accounts, NewCursor(accounts[len(accounts)-1])
+}
+
+// ErrInvalidCursor is returned when a Cursor was not created by NewCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is an opaque keyset pagination cursor.
+// Paginated queries are ordered by (created_at, id), and the next page starts
+// after the (created_at, id) encoded in the cursor, so that rows inserted or
+// deleted between pages do not cause duplicates or gaps like OFFSET would.
+type Cursor string
+
+// Keyset is the decoded position of a Cursor
+type Keyset struct {
+	CreatedAt time.Time
+	ID        uint64
+}
+
+// NewCursor returns the cursor of the page that starts after the account
+func NewCursor(account AccountIdeal) Cursor {
+	keyset := account.CreatedAt.UTC().Format(time.RFC3339Nano) + " " + strconv.FormatUint(account.ID, 10)
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(keyset)))
+}
+
+// Keyset decodes the cursor, returning false if the cursor is empty
+func (c Cursor) Keyset() (Keyset, bool, error) {
+	if c == "" {
+		return Keyset{}, false, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return Keyset{}, false, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	createdAt, id, ok := strings.Cut(string(decoded), " ")
+	if !ok {
+		return Keyset{}, false, ErrInvalidCursor
+	}
+
+	var keyset Keyset
+	if keyset.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return Keyset{}, false, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if keyset.ID, err = strconv.ParseUint(id, 10, 64); err != nil {
+		return Keyset{}, false, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return keyset, true, nil
+}
+
+// ErrInvalidColor is returned when a Color is not a value of the colors enum
+var ErrInvalidColor = errors.New("invalid color")
+
+// Color is a value of the postgres COLORS enum.
+// It validates itself when parsed, scanned, unmarshalled, or passed as an arg,
+// so that an invalid color is rejected before it reaches postgres.
+// It marshals to and from json as a string, through MarshalText and
+// UnmarshalText.
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+	ColorBlue  Color = "blue"
+)
+
+// Colors is every value of the colors enum, in the order of the enum
+var Colors = []Color{ColorRed, ColorGreen, ColorBlue}
+
+// ParseColor returns the color, or an error wrapping ErrInvalidColor if s is
+// not a value of the colors enum
+func ParseColor(s string) (Color, error) {
+	c := Color(s)
+	return c, c.Validate()
+}
+
+// Validate returns an error wrapping ErrInvalidColor if c is not a value of
+// the colors enum
+func (c Color) Validate() error {
+	switch c {
+	case ColorRed, ColorGreen, ColorBlue:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidColor, string(c))
+	}
+}
+
+// ValidateColors returns an error for the first color that is not valid
+func ValidateColors(colors []Color) error {
+	for _, c := range colors {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Color) String() string {
+	return string(c)
+}
+
+func (c Color) MarshalText() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(c), nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	color, err := ParseColor(string(text))
+	if err != nil {
+		return err
+	}
+	*c = color
+	return nil
+}
+
+// Scan is used by database/sql.
+// A NULL color has to be scanned into a *Color.
+func (c *Color) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(src))
+	case []byte:
+		return c.UnmarshalText(src)
+	default:
+		return fmt.Errorf("cannot scan %T into models.Color", src)
+	}
+}
+
+// Value is used by database/sql, and by pgx if RegisterColors was not called
+func (c Color) Value() (driver.Value, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return string(c), nil
+}
+
+// TextValue is used by pgx's enum codec once RegisterColors has been called,
+// as pgx would otherwise encode the underlying string without calling Value
+func (c Color) TextValue() (pgtype.Text, error) {
+	if err := c.Validate(); err != nil {
+		return pgtype.Text{}, err
+	}
+	return pgtype.Text{String: string(c), Valid: true}, nil
+}
+
+// ScanText is used by pgx's enum codec once RegisterColors has been called,
+// as pgx would otherwise scan into the underlying string without calling Scan
+func (c *Color) ScanText(v pgtype.Text) error {
+	if !v.Valid {
+		return errors.New("cannot scan NULL into models.Color")
+	}
+	return c.UnmarshalText([]byte(v.String))
+}
+
+// RegisterColors registers the COLORS enum and COLORS[] array types with a pgx
+// connection. It is meant to be used as the AfterConnect hook of a pgxpool, or
+// with stdlib.OptionAfterConnect for database/sql.
+// Without it, pgx does not know how to encode a []Color as a COLORS[], because
+// the OIDs of user defined types differ between databases.
+func RegisterColors(ctx context.Context, conn *pgx.Conn) error {
+	// The array's element type has to be registered first
+	for _, name := range []string{"colors", "_colors"} {
+		dataType, err := conn.LoadType(ctx, name)
+		if err != nil {
+			return err
+		}
+		conn.TypeMap().RegisterType(dataType)
+	}
+	// Used when the type of an arg is not known, such as with the simple protocol
+	conn.TypeMap().RegisterDefaultPgType(Color(""), "colors")
+	conn.TypeMap().RegisterDefaultPgType([]Color(nil), "_colors")
+	return nil
+}
+
+// AccountPatch exists to test out dynamic updates.
+// Only the fields that are not nil are updated.
+// Setting FavColor to a pointer to a nil *Color, FavNumbers to a pointer to a
+// nil slice, or Properties to a pointer to an invalid JSONB, updates the column
+// to NULL.
+type AccountPatch struct {
+	Name       *string
+	Email      *string
+	Active     *bool
+	FavColor   **Color
+	FavNumbers *[]int
+	Properties *JSONB[AccountProperties]
+}
+
+// IsEmpty returns true if the patch would not update any fields
+func (p AccountPatch) IsEmpty() bool {
+	return p == AccountPatch{}
+}
+
+// AccountRepository is the common contract that every library example's DAO
+// implements, so that implementations can be swapped for one another.
+type AccountRepository interface {
+	// SelectAccountByID returns false if the account does not exist
+	SelectAccountByID(ctx context.Context, id uint64) (AccountIdeal, bool, error)
+	SelectAllAccounts(ctx context.Context) ([]AccountIdeal, error)
+	// SelectAllAccountsByFilter returns the matching accounts ordered by
+	// (created_at, id), one page at a time if filters.PageSize is set, along
+	// with the cursor of the next page, which is empty on the last page.
+	SelectAllAccountsByFilter(ctx context.Context, filters Filters) ([]AccountIdeal, Cursor, error)
+	// CreateAccount inserts the account, ignoring its ID, and returns the
+	// inserted row with the ID assigned by the database
+	CreateAccount(ctx context.Context, account AccountIdeal) (AccountIdeal, error)
+	// UpdateAccount updates only the fields set in the patch, and returns the
+	// updated row, or false if the account does not exist.
+	// An empty patch returns the account unchanged.
+	UpdateAccount(ctx context.Context, id uint64, patch AccountPatch) (AccountIdeal, bool, error)
+	// SelectAccountWithOrders returns the account with its orders ordered by
+	// id, from a single query joining the two tables, or false if the account
+	// does not exist.
+	// Orders is nil if the account does not have any orders.
+	SelectAccountWithOrders(ctx context.Context, id uint64) (AccountIdeal, bool, error)
+}
+
+// AccountUpserter is implemented by the examples that demonstrate handling a
+// conflict on the unique email column with INSERT ... ON CONFLICT.
+type AccountUpserter interface {
+	// UpsertAccountByEmail inserts the account, ignoring its ID, or if an
+	// account with the same email already exists, updates every column except
+	// the ID and CreatedAt.
+	// It returns the final row, and true if it was inserted or false if it was
+	// updated, which postgres can tell apart using xmax = 0.
+	UpsertAccountByEmail(ctx context.Context, account AccountIdeal) (AccountIdeal, bool, error)
+}
+
+// AccountBulkInserter is implemented by the examples that demonstrate loading
+// many accounts at once, either with COPY or with multi-row INSERT ... VALUES.
+type AccountBulkInserter interface {
+	// BulkInsertAccounts inserts the accounts, ignoring their IDs, and returns
+	// the number of rows inserted.
+	BulkInsertAccounts(ctx context.Context, accounts []AccountIdeal) (int64, error)
+}
+
+// AccountTagSelector is implemented by the examples that demonstrate loading
+// a many to many relationship, either by aggregating it into an array or json
+// column per account, or by folding the rows of a flat join.
+type AccountTagSelector interface {
+	// SelectAllAccountsWithTags returns every account ordered by id, each with
+	// its tags ordered by name.
+	// Tags is nil if the account does not have any tags.
+	SelectAllAccountsWithTags(ctx context.Context) ([]AccountIdeal, error)
+}
+
+// SQLQuerier is implemented by both *sql.DB and *sql.Tx, so that the DAOs
+// using database/sql can run against either the pool or a transaction.
+type SQLQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// PgxQuerier is implemented by *pgxpool.Pool, *pgx.Conn, and pgx.Tx, so that
+// the DAOs using pgx can run against either the pool or a transaction.
+type PgxQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 }
 
 // AccountIdeal is the ideal model for an "accounts" row we would like to use,
 // with hope that our driver and helper library can directly use this.
 type AccountIdeal struct {
-	ID         uint64           `json:"id" db:"id" ksql:"id"`
-	Name       string           `json:"name" db:"name" ksql:"name"`
-	Email      string           `json:"email" db:"email" ksql:"email"`
-	Active     bool             `json:"active" db:"active" ksql:"active"`
-	FavColor   *string          `json:"fav_color" db:"fav_color" ksql:"fav_color"`       // Can be null // Could also use sql.NullString or Nullable instead of *string
-	FavNumbers []int            `json:"fav_numbers" db:"fav_numbers" ksql:"fav_numbers"` // Can be null
-	Properties *json.RawMessage `json:"properties" db:"properties" ksql:"properties"`    // Can be null // Could also use []byte instead of *json.RawMessage
-	CreatedAt  time.Time        `json:"created_at" db:"created_at" ksql:"created_at"`
+	ID         uint64                   `json:"id" db:"id" ksql:"id" bun:"id,pk,autoincrement"`
+	Name       string                   `json:"name" db:"name" ksql:"name" bun:"name"`
+	Email      string                   `json:"email" db:"email" ksql:"email" bun:"email"`
+	Active     bool                     `json:"active" db:"active" ksql:"active" bun:"active"`
+	FavColor   *Color                   `json:"fav_color" db:"fav_color" ksql:"fav_color" bun:"fav_color"`               // Can be null
+	FavNumbers []int                    `json:"fav_numbers" db:"fav_numbers" ksql:"fav_numbers" bun:"fav_numbers,array"` // Can be null
+	Properties JSONB[AccountProperties] `json:"properties" db:"properties" ksql:"properties" bun:"properties"`           // Can be null, see JSONB.Valid
+	CreatedAt  time.Time                `json:"created_at" db:"created_at" ksql:"created_at" bun:"created_at"`
+
+	// Orders is only filled in by SelectAccountWithOrders, and is not a column
+	Orders []Order `json:"orders,omitempty" db:"-" bun:"-"`
+	// Tags is only filled in by SelectAllAccountsWithTags, and is not a column
+	Tags []Tag `json:"tags,omitempty" db:"-" bun:"-"`
 }
 
 func (a AccountIdeal) String() string {
-	return fmt.Sprintf("Account:\nID: %d\nName: %s\nEmail: %s\nActive: %t\nFavColor: %s\nFavNumbers: %v\nProperties: %s\nCreatedAt: %s",
+	str := fmt.Sprintf("Account:\nID: %d\nName: %s\nEmail: %s\nActive: %t\nFavColor: %s\nFavNumbers: %v\nProperties: %s\nCreatedAt: %s",
 		a.ID,
 		a.Name,
 		a.Email,
 		a.Active,
 		PtrToStr(a.FavColor),
 		SliceToStr(a.FavNumbers),
-		PtrToStr(a.Properties),
+		a.Properties,
 		a.CreatedAt)
+	for _, order := range a.Orders {
+		str += "\n" + order.String()
+	}
+	if a.Tags != nil {
+		str += fmt.Sprintf("\nTags: %v", a.Tags)
+	}
+	return str
+}
+
+// Order is a row of the orders table, which references accounts.id
+type Order struct {
+	ID         uint64    `json:"id" db:"id" ksql:"id"`
+	AccountID  uint64    `json:"account_id" db:"account_id" ksql:"account_id"`
+	Product    string    `json:"product" db:"product" ksql:"product"`
+	Quantity   int       `json:"quantity" db:"quantity" ksql:"quantity"`
+	PriceCents int64     `json:"price_cents" db:"price_cents" ksql:"price_cents"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" ksql:"created_at"`
+}
+
+func (o Order) String() string {
+	return fmt.Sprintf("Order %d: %d x %s at %d cents, %s", o.ID, o.Quantity, o.Product, o.PriceCents, o.CreatedAt)
+}
+
+// NullOrder is the orders columns of accounts LEFT JOIN orders, which are all
+// NULL for an account without any orders
+type NullOrder struct {
+	ID         *uint64    `json:"id" db:"id" ksql:"id"`
+	AccountID  *uint64    `json:"account_id" db:"account_id" ksql:"account_id"`
+	Product    *string    `json:"product" db:"product" ksql:"product"`
+	Quantity   *int       `json:"quantity" db:"quantity" ksql:"quantity"`
+	PriceCents *int64     `json:"price_cents" db:"price_cents" ksql:"price_cents"`
+	CreatedAt  *time.Time `json:"created_at" db:"created_at" ksql:"created_at"`
+}
+
+// Order returns the order, or false if the columns were NULL
+func (o NullOrder) Order() (Order, bool) {
+	if o.ID == nil {
+		return Order{}, false
+	}
+	// Every other column is NOT NULL, so they are set whenever the ID is
+	return Order{
+		ID:         *o.ID,
+		AccountID:  *o.AccountID,
+		Product:    *o.Product,
+		Quantity:   *o.Quantity,
+		PriceCents: *o.PriceCents,
+		CreatedAt:  *o.CreatedAt,
+	}, true
+}
+
+// Tag is a row of the tags table, which is related to accounts through the
+// account_tags table
+type Tag struct {
+	ID   uint64 `json:"id" db:"id" ksql:"id"`
+	Name string `json:"name" db:"name" ksql:"name"`
+}
+
+func (t Tag) String() string {
+	return t.Name
+}
+
+// Tags is a wrapper that allows scanning a json array of tags, such as from
+// json_agg, for the libraries that go through database/sql, which only hands
+// a json column to a sql.Scanner as bytes
+type Tags []Tag
+
+func (t *Tags) Scan(src any) error {
+	*t = nil // Unmarshal would otherwise reuse the existing elements
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	default:
+		return fmt.Errorf("cannot scan %T into models.Tags", src)
+	}
+}
+
+// Compatible converts to an AccountCompatible, for libraries that can only
+// insert from an AccountCompatible.
+func (a AccountIdeal) Compatible() AccountCompatible {
+	return AccountCompatible{
+		ID:         a.ID,
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   a.FavColor,
+		FavNumbers: a.FavNumbers,
+		Properties: a.Properties,
+		CreatedAt:  a.CreatedAt,
+	}
 }
 
 // AccountCompatible is not as nice as AccountIdeal, because it has to use a
@@ -49,14 +532,14 @@ func (a AccountIdeal) String() string {
 // This is usually because the helper library is forced to use the stdlib
 // version of pgx.
 type AccountCompatible struct {
-	ID         uint64           `json:"id" db:"id" ksql:"id"`
-	Name       string           `json:"name" db:"name" ksql:"name"`
-	Email      string           `json:"email" db:"email" ksql:"email"`
-	Active     bool             `json:"active" db:"active" ksql:"active"`
-	FavColor   *string          `json:"fav_color" db:"fav_color" ksql:"fav_color"`       // Can be null // Could also use sql.NullString or Nullable instead of *string
-	FavNumbers Array[int]       `json:"fav_numbers" db:"fav_numbers" ksql:"fav_numbers"` // Can be null
-	Properties *json.RawMessage `json:"properties" db:"properties" ksql:"properties"`    // Can be null // Could also use []byte instead of *json.RawMessage
-	CreatedAt  time.Time        `json:"created_at" db:"created_at" ksql:"created_at"`
+	ID         uint64                   `json:"id" db:"id" ksql:"id" gorm:"column:id;primaryKey"`
+	Name       string                   `json:"name" db:"name" ksql:"name" gorm:"column:name"`
+	Email      string                   `json:"email" db:"email" ksql:"email" gorm:"column:email"`
+	Active     bool                     `json:"active" db:"active" ksql:"active" gorm:"column:active"`
+	FavColor   *Color                   `json:"fav_color" db:"fav_color" ksql:"fav_color" gorm:"column:fav_color"`                        // Can be null
+	FavNumbers Array[int]               `json:"fav_numbers" db:"fav_numbers" ksql:"fav_numbers" gorm:"column:fav_numbers;type:integer[]"` // Can be null
+	Properties JSONB[AccountProperties] `json:"properties" db:"properties" ksql:"properties" gorm:"column:properties;type:jsonb"`         // Can be null, see JSONB.Valid
+	CreatedAt  time.Time                `json:"created_at" db:"created_at" ksql:"created_at" gorm:"column:created_at;autoCreateTime:false"`
 }
 
 func (a AccountCompatible) String() string {
@@ -67,10 +550,117 @@ func (a AccountCompatible) String() string {
 		a.Active,
 		PtrToStr(a.FavColor),
 		SliceToStr(a.FavNumbers),
-		PtrToStr(a.Properties),
+		a.Properties,
 		a.CreatedAt)
 }
 
+// Ideal converts to an AccountIdeal, for libraries that can only scan into
+// an AccountCompatible.
+func (a AccountCompatible) Ideal() AccountIdeal {
+	return AccountIdeal{
+		ID:         a.ID,
+		Name:       a.Name,
+		Email:      a.Email,
+		Active:     a.Active,
+		FavColor:   a.FavColor,
+		FavNumbers: a.FavNumbers.Get(),
+		Properties: a.Properties,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// CompatibleToIdeal converts a slice of AccountCompatible to AccountIdeal,
+// preserving a nil slice.
+func CompatibleToIdeal(accounts []AccountCompatible) []AccountIdeal {
+	if accounts == nil {
+		return nil
+	}
+	ideals := make([]AccountIdeal, 0, len(accounts))
+	for _, account := range accounts {
+		ideals = append(ideals, account.Ideal())
+	}
+	return ideals
+}
+
+// AccountProperties is the json stored in the accounts.properties column
+type AccountProperties struct {
+	Tags []string `json:"tags,omitempty"`
+}
+
+// JSONB is a wrapper that allows scanning a json or jsonb column straight into
+// a typed golang value, instead of a json.RawMessage that every caller has to
+// parse again, and passing it back in as an arg.
+// Valid is false for NULL, so that NULL and '{}' can be told apart.
+// It works with database/sql as a sql.Scanner and driver.Valuer, and with pgx,
+// because pgx's json and jsonb codecs check for those two interfaces before
+// falling back to json.Marshal and json.Unmarshal, which can not handle NULL.
+type JSONB[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewJSONB returns a valid JSONB holding v
+func NewJSONB[T any](v T) JSONB[T] {
+	return JSONB[T]{V: v, Valid: true}
+}
+
+func (j *JSONB[T]) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*j = JSONB[T]{}
+		return nil
+	case []byte:
+		return j.UnmarshalJSON(src)
+	case string:
+		return j.UnmarshalJSON([]byte(src))
+	default:
+		return fmt.Errorf("cannot scan %T into models.JSONB", src)
+	}
+}
+
+// Value returns the json as a string, because some libraries, such as goqu,
+// would treat []byte as bytea or a list.
+func (j JSONB[T]) Value() (driver.Value, error) {
+	if !j.Valid {
+		return nil, nil
+	}
+	b, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (j JSONB[T]) MarshalJSON() ([]byte, error) {
+	if !j.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.V)
+}
+
+func (j *JSONB[T]) UnmarshalJSON(data []byte) error {
+	*j = JSONB[T]{} // Unmarshal would otherwise merge into the existing value
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, &j.V); err != nil {
+		return err
+	}
+	j.Valid = true
+	return nil
+}
+
+func (j JSONB[T]) String() string {
+	if !j.Valid {
+		return "<nil>"
+	}
+	b, err := json.Marshal(j.V)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
 // Array is a wrapper that allows scanning a postgres Array into a golang slice
 type Array[T any] []T
 
@@ -84,9 +674,12 @@ func (a *Array[T]) Scan(src any) error {
 }
 
 func (a Array[T]) Value() (driver.Value, error) {
-	if len(a) == 0 {
+	if a == nil {
 		return nil, nil
 	}
+	if len(a) == 0 {
+		return "{}", nil
+	}
 	src := pgtype.FlatArray[T](a)
 	arrayType, ok1 := pgTypeMap.TypeForValue(src)
 	elementType, ok2 := pgTypeMap.TypeForValue(src[0])
@@ -108,6 +701,40 @@ func (a Array[T]) Get() []T {
 
 var pgTypeMap = pgtype.NewMap()
 
+// MaxParams is the most bind parameters postgres allows in a single statement
+const MaxParams = 65535
+
+// Chunk splits rows into chunks that each stay under MaxParams, when each row
+// uses paramsPerRow bind parameters, such as in a multi-row INSERT ... VALUES.
+// Rows without any parameters are never over the limit, so are a single chunk,
+// and rows with more parameters than the limit each get their own chunk, which
+// postgres will then reject.
+func Chunk[T any](rows []T, paramsPerRow int) [][]T {
+	if paramsPerRow <= 0 {
+		if len(rows) == 0 {
+			return [][]T{}
+		}
+		return [][]T{rows}
+	}
+	size := max(MaxParams/paramsPerRow, 1)
+	chunks := make([][]T, 0, (len(rows)+size-1)/size)
+	for size < len(rows) {
+		chunks = append(chunks, rows[:size:size])
+		rows = rows[size:]
+	}
+	if len(rows) > 0 {
+		chunks = append(chunks, rows)
+	}
+	return chunks
+}
+
+// LogQuery is called by the examples with each dynamically built query, to
+// show the SQL and args that each library generates.
+// It can be replaced, for example to silence it while benchmarking.
+var LogQuery = func(query string, args []any) {
+	fmt.Printf("--------\nDynamic Query SQL:\n%s\n\nDynamic Query Args:\n%#+v\n", query, args)
+}
+
 /*
 The following are just helpers for our own debugging and pretty-printing
 */
@@ -118,8 +745,8 @@ func PtrToStr[T any](v *T) string {
 		return "<nil>"
 	}
 	// Hack for displaying []byte as a string
-	if refType := reflect.TypeOf(*v); refType.Kind() == reflect.Slice && refType.Elem().Kind() == reflect.Uint8 {
-		return fmt.Sprintf("%s", *v)
+	if refVal := reflect.ValueOf(*v); refVal.Kind() == reflect.Slice && refVal.Type().Elem().Kind() == reflect.Uint8 {
+		return string(refVal.Bytes())
 	}
 	return fmt.Sprint(*v)
 }
@@ -130,8 +757,8 @@ func SliceToStr[T any](v []T) string {
 		return "<nil>"
 	}
 	// Hack for displaying []byte as a string
-	if refType := reflect.TypeOf(v); refType.Kind() == reflect.Slice && refType.Elem().Kind() == reflect.Uint8 {
-		return fmt.Sprintf("%s", v)
+	if refVal := reflect.ValueOf(v); refVal.Type().Elem().Kind() == reflect.Uint8 {
+		return string(refVal.Bytes())
 	}
 	return fmt.Sprint(v)
 }
@@ -147,8 +774,8 @@ func (n Nullable[T]) String() string {
 	}
 
 	// Hack for displaying []byte as a string
-	if refType := reflect.TypeOf(n.V); refType.Kind() == reflect.Slice && refType.Elem().Kind() == reflect.Uint8 {
-		return fmt.Sprintf("%s", n.V)
+	if refVal := reflect.ValueOf(n.V); refVal.Kind() == reflect.Slice && refVal.Type().Elem().Kind() == reflect.Uint8 {
+		return string(refVal.Bytes())
 	}
 	return fmt.Sprintf("%v", n.V)
 }
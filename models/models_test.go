@@ -0,0 +1,360 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestChunk(t *testing.T) {
+	const paramsPerRow = 7
+	size := MaxParams / paramsPerRow
+
+	tests := []struct {
+		rows int
+		want []int
+	}{
+		{rows: 0, want: []int{}},
+		{rows: 1, want: []int{1}},
+		{rows: size, want: []int{size}},
+		{rows: size + 1, want: []int{size, 1}},
+		{rows: 2*size + 5, want: []int{size, size, 5}},
+	}
+
+	for _, tc := range tests {
+		chunks := Chunk(make([]int, tc.rows), paramsPerRow)
+		got := make([]int, 0, len(chunks))
+		for _, chunk := range chunks {
+			if len(chunk)*paramsPerRow > MaxParams {
+				t.Errorf("%d rows: chunk of %d rows is over the parameter limit", tc.rows, len(chunk))
+			}
+			got = append(got, len(chunk))
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%d rows: expected chunk sizes %v, got %v", tc.rows, tc.want, got)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%d rows: expected chunk sizes %v, got %v", tc.rows, tc.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestChunkParamsPerRow(t *testing.T) {
+	tests := []struct {
+		name         string
+		paramsPerRow int
+		rows         int
+		want         []int
+	}{
+		{name: "zero", paramsPerRow: 0, rows: 3, want: []int{3}},
+		{name: "zero empty", paramsPerRow: 0, rows: 0, want: []int{}},
+		{name: "negative", paramsPerRow: -1, rows: 3, want: []int{3}},
+		{name: "over the limit", paramsPerRow: MaxParams + 1, rows: 3, want: []int{1, 1, 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := Chunk(make([]int, tc.rows), tc.paramsPerRow)
+			got := make([]int, 0, len(chunks))
+			for _, chunk := range chunks {
+				got = append(got, len(chunk))
+			}
+			if !slices.Equal(tc.want, got) {
+				t.Errorf("expected chunk sizes %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	account := AccountIdeal{ID: 42, CreatedAt: time.Date(2024, 8, 28, 1, 2, 3, 456789000, time.FixedZone("EST", -5*60*60))}
+	cursor := NewCursor(account)
+
+	keyset, ok, err := cursor.Keyset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected a keyset")
+	}
+	if keyset.ID != account.ID || !keyset.CreatedAt.Equal(account.CreatedAt) {
+		t.Errorf("expected keyset (%s, %d), got (%s, %d)", account.CreatedAt, account.ID, keyset.CreatedAt, keyset.ID)
+	}
+
+	if _, ok, err = Cursor("").Keyset(); ok || err != nil {
+		t.Errorf("expected no keyset and no error for an empty cursor, got %t, %v", ok, err)
+	}
+
+	invalids := []Cursor{
+		"not base64!",
+		Cursor(base64.RawURLEncoding.EncodeToString([]byte("no-space"))),
+		Cursor(base64.RawURLEncoding.EncodeToString([]byte("yesterday 1"))),
+		Cursor(base64.RawURLEncoding.EncodeToString([]byte("2024-08-28T01:02:03Z -1"))),
+	}
+	for _, invalid := range invalids {
+		if _, _, err = invalid.Keyset(); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: expected ErrInvalidCursor, got: %v", invalid, err)
+		}
+	}
+}
+
+func TestFiltersPage(t *testing.T) {
+	accounts := []AccountIdeal{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	tests := []struct {
+		pageSize  int
+		wantLimit int
+		wantLen   int
+		wantNext  Cursor
+	}{
+		{pageSize: 0, wantLimit: 0, wantLen: 3, wantNext: ""},
+		{pageSize: 2, wantLimit: 3, wantLen: 2, wantNext: NewCursor(accounts[1])},
+		{pageSize: 3, wantLimit: 4, wantLen: 3, wantNext: ""},
+	}
+
+	for _, tc := range tests {
+		filters := Filters{PageSize: tc.pageSize}
+		if limit := filters.Limit(); limit != tc.wantLimit {
+			t.Errorf("page size %d: expected limit %d, got %d", tc.pageSize, tc.wantLimit, limit)
+		}
+		page, next := filters.Page(accounts)
+		if len(page) != tc.wantLen || next != tc.wantNext {
+			t.Errorf("page size %d: expected %d accounts and cursor %q, got %d and %q", tc.pageSize, tc.wantLen, tc.wantNext, len(page), next)
+		}
+	}
+}
+
+func TestFiltersOrderBy(t *testing.T) {
+	sorts, err := Filters{}.OrderBy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := OrderBySQL(sorts); got != "created_at, id" {
+		t.Errorf("expected the default sort, got %q", got)
+	}
+	// Changing the returned default sort must not change it for later queries
+	sorts[0].Desc = true
+	_ = append(sorts[:1], Sort{Column: SortByName})
+	if got := OrderBySQL(DefaultSort); got != "created_at, id" {
+		t.Errorf("expected DefaultSort to be unchanged, got %q", got)
+	}
+
+	filters := Filters{Sort: []Sort{
+		{Column: SortByFavColor, Desc: true, Nulls: NullsLast},
+		{Column: SortByName, Nulls: NullsFirst},
+		{Column: SortByID, Desc: true},
+	}}
+	sorts, err = filters.OrderBy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "fav_color DESC NULLS LAST, name NULLS FIRST, id DESC"
+	if got := OrderBySQL(sorts); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	// Changing the returned sort must not change the caller's filters
+	sorts[0].Desc = false
+	_ = append(sorts[:1], Sort{Column: SortByEmail})
+	if got := OrderBySQL(filters.Sort); got != want {
+		t.Errorf("expected the filters' sort to be unchanged, got %q", got)
+	}
+
+	invalids := []Filters{
+		{Sort: []Sort{{Column: "id; DROP TABLE accounts"}}},
+		{Sort: []Sort{{Column: "ID"}}},
+		{Sort: []Sort{{Column: SortByID}, {Column: "fav_numbers"}}},
+		{Sort: []Sort{{Column: SortByID, Nulls: "first"}}},
+		{Sort: []Sort{{Column: SortByID}}, PageSize: 10},
+		{Sort: []Sort{{Column: SortByID}}, Cursor: NewCursor(AccountIdeal{ID: 1})},
+	}
+	for _, filters := range invalids {
+		if _, err = filters.OrderBy(); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("%+v: expected ErrInvalidSort, got: %v", filters, err)
+		}
+	}
+}
+
+func TestTagsScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Tags
+		wantErr bool
+	}{
+		{name: "null", src: nil, want: nil},
+		{name: "bytes", src: []byte(`[{"id": 1, "name": "admin"}, {"id": 3, "name": "vip"}]`), want: Tags{{ID: 1, Name: "admin"}, {ID: 3, Name: "vip"}}},
+		{name: "string", src: `[{"id": 2, "name": "beta"}]`, want: Tags{{ID: 2, Name: "beta"}}},
+		{name: "empty", src: `[]`, want: Tags{}},
+		{name: "invalid json", src: `[{"id": "one"}]`, wantErr: true},
+		{name: "invalid type", src: int64(1), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Tags{{ID: 9, Name: "stale"}}
+			err := got.Scan(tc.src)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("expected %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONB(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   any
+		want  JSONB[AccountProperties]
+		value driver.Value
+	}{
+		{name: "null", src: nil, want: JSONB[AccountProperties]{}, value: nil},
+		{name: "empty", src: []byte(`{}`), want: NewJSONB(AccountProperties{}), value: `{}`},
+		{name: "bytes", src: []byte(`{"tags": ["fun"]}`), want: NewJSONB(AccountProperties{Tags: []string{"fun"}}), value: `{"tags":["fun"]}`},
+		{name: "string", src: `{"tags": ["a", "b"]}`, want: NewJSONB(AccountProperties{Tags: []string{"a", "b"}}), value: `{"tags":["a","b"]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewJSONB(AccountProperties{Tags: []string{"stale"}})
+			if err := got.Scan(tc.src); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("expected %#v, got %#v", tc.want, got)
+			}
+
+			value, err := got.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tc.value {
+				t.Errorf("expected value %#v, got %#v", tc.value, value)
+			}
+
+			// NULL marshals to json null, and round trips back to NULL
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var roundTrip JSONB[AccountProperties]
+			if err = json.Unmarshal(b, &roundTrip); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, roundTrip) {
+				t.Errorf("expected %#v after a round trip through %s, got %#v", got, b, roundTrip)
+			}
+		})
+	}
+
+	var invalid JSONB[AccountProperties]
+	if err := invalid.Scan(`{"tags": "fun"}`); err == nil {
+		t.Errorf("expected an error scanning invalid json, got %#v", invalid)
+	}
+	if err := invalid.Scan(int64(1)); err == nil {
+		t.Errorf("expected an error scanning an int, got %#v", invalid)
+	}
+}
+
+func TestColor(t *testing.T) {
+	for _, color := range Colors {
+		parsed, err := ParseColor(string(color))
+		if err != nil || parsed != color {
+			t.Errorf("expected %q to parse, got %q, %v", color, parsed, err)
+		}
+	}
+	for _, invalid := range []string{"", "purple", "Red"} {
+		if _, err := ParseColor(invalid); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("expected ErrInvalidColor for %q, got: %v", invalid, err)
+		}
+	}
+
+	if err := ValidateColors([]Color{ColorRed, "purple"}); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor, got: %v", err)
+	}
+	if err := ValidateColors(nil); err != nil {
+		t.Errorf("expected no colors to be valid, got: %v", err)
+	}
+}
+
+func TestColorSQL(t *testing.T) {
+	var color Color
+	for _, src := range []any{"green", []byte("green")} {
+		if err := color.Scan(src); err != nil || color != ColorGreen {
+			t.Errorf("expected %#v to scan to green, got %q, %v", src, color, err)
+		}
+	}
+	for _, src := range []any{nil, "purple", int64(1)} {
+		if err := color.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %#v, got %q", src, color)
+		}
+	}
+
+	if err := color.ScanText(pgtype.Text{String: "red", Valid: true}); err != nil || color != ColorRed {
+		t.Errorf("expected red, got %q, %v", color, err)
+	}
+	if err := color.ScanText(pgtype.Text{String: "purple", Valid: true}); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor, got: %v", err)
+	}
+
+	value, err := ColorBlue.Value()
+	if err != nil || value != "blue" {
+		t.Errorf("expected blue, got %#v, %v", value, err)
+	}
+	if _, err = Color("purple").Value(); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor, got: %v", err)
+	}
+	if _, err = Color("purple").TextValue(); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor, got: %v", err)
+	}
+}
+
+func TestColorJSON(t *testing.T) {
+	type wrapper struct {
+		FavColor  *Color  `json:"fav_color"`
+		FavColors []Color `json:"fav_colors"`
+	}
+	red, purple := ColorRed, Color("purple")
+	want := wrapper{FavColor: &red, FavColors: []Color{ColorGreen, ColorBlue}}
+
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"fav_color":"red","fav_colors":["green","blue"]}` {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	var got wrapper
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %#v, got %#v", want, got)
+	}
+
+	if err = json.Unmarshal([]byte(`{"fav_color":"purple"}`), &got); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor unmarshalling, got: %v", err)
+	}
+	if _, err = json.Marshal(wrapper{FavColor: &purple}); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("expected ErrInvalidColor marshalling, got: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package txn
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// pgxTxKey is the context key of the current pgx transaction
+type pgxTxKey struct{}
+
+// PgxBeginner is implemented by *pgxpool.Pool and *pgx.Conn
+type PgxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// WithPgxTx runs fn in a pgx transaction, see the package docs.
+// If ctx comes from an outer WithPgxTx, db and opts are ignored, and fn runs in
+// a savepoint of the outer transaction instead, which is not retried on its
+// own.
+func WithPgxTx(ctx context.Context, db PgxBeginner, opts pgx.TxOptions, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	// pgx already implements nested transactions as savepoints
+	if outer, ok := ctx.Value(pgxTxKey{}).(pgx.Tx); ok {
+		return pgx.BeginFunc(ctx, outer, func(tx pgx.Tx) error {
+			return fn(context.WithValue(ctx, pgxTxKey{}, tx), tx)
+		})
+	}
+
+	return retry(ctx, func() error {
+		return pgx.BeginTxFunc(ctx, db, opts, func(tx pgx.Tx) error {
+			return fn(context.WithValue(ctx, pgxTxKey{}, tx), tx)
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// sqlTxKey is the context key of the current database/sql transaction
+type sqlTxKey struct{}
+
+// sqlTx is stored in the context, so that nested calls use savepoints
+type sqlTx struct {
+	tx    *sql.Tx
+	depth int
+}
+
+// WithTx runs fn in a database/sql transaction, see the package docs.
+// If ctx comes from an outer WithTx, db and opts are ignored, and fn runs in a
+// savepoint of the outer transaction instead, which is not retried on its own.
+func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	if outer, ok := ctx.Value(sqlTxKey{}).(sqlTx); ok {
+		return withSavepoint(ctx, outer, fn)
+	}
+
+	return retry(ctx, func() error {
+		tx, err := db.BeginTx(ctx, opts)
+		if err != nil {
+			return err
+		}
+		// Rollback does nothing if the transaction was already committed,
+		// and makes sure the transaction is rolled back after a panic.
+		defer tx.Rollback()
+
+		if err = fn(context.WithValue(ctx, sqlTxKey{}, sqlTx{tx: tx}), tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// withSavepoint runs fn in a savepoint of the outer transaction
+func withSavepoint(ctx context.Context, outer sqlTx, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
+	inner := sqlTx{tx: outer.tx, depth: outer.depth + 1}
+	savepoint := fmt.Sprintf("sp_%d", inner.depth)
+
+	if _, err = inner.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = inner.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, sqlTxKey{}, inner), inner.tx); err != nil {
+		if _, rbErr := inner.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	_, err = inner.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	return err
+}
@@ -0,0 +1,81 @@
+/*
+Package txn runs functions inside a transaction, for both database/sql and pgx.
+The transaction is committed if the function returns nil, and rolled back if it
+returns an error or panics.
+If postgres aborts the transaction with a serialization failure (40001) or a
+deadlock (40P01), the whole transaction is retried with a backoff, so the
+function must be safe to run more than once.
+Calling WithTx or WithPgxTx again with the context given to the function runs
+the inner function in a savepoint of the same transaction, so that only the
+inner function's changes are rolled back if it returns an error.
+
+Every DAO can be constructed against either the pool or the transaction, so
+that multiple repositories can share a transaction:
+
+	err := txn.WithPgxTx(ctx, pool, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		repo := dao.New(tx)
+		...
+	})
+*/
+package txn
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MaxAttempts is the number of times a transaction is run before giving up on
+// serialization failures and deadlocks
+var MaxAttempts = 5
+
+// maxBackoffShift caps the doubling of Backoff at about 10 seconds, which also
+// keeps the shift from overflowing the duration
+const maxBackoffShift = 10
+
+// Backoff returns how long to wait before retrying the transaction after the
+// given attempt, which starts at 1.
+// It doubles with each attempt, up to about 10 seconds, with jitter so that
+// competing transactions do not retry in lockstep.
+var Backoff = func(attempt int) time.Duration {
+	backoff := 10 * time.Millisecond << min(max(attempt-1, 0), maxBackoffShift)
+	return backoff/2 + rand.N(backoff/2)
+}
+
+// Retryable returns true if postgres aborted the transaction because of a
+// serialization failure or a deadlock, in which case it can be retried.
+func Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "40001", // serialization_failure
+		"40P01": // deadlock_detected
+		return true
+	default:
+		return false
+	}
+}
+
+// retry runs fn until it succeeds, returns an error that is not Retryable, or
+// MaxAttempts is reached
+func retry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !Retryable(err) || attempt >= MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
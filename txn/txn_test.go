@@ -0,0 +1,226 @@
+package txn_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/veqryn/awesome-go-sql/internal/conformance"
+	"github.com/veqryn/awesome-go-sql/txn"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: nil, want: false},
+		{err: errors.New("boom"), want: false},
+		{err: &pgconn.PgError{Code: "40001"}, want: true},
+		{err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{err: &pgconn.PgError{Code: "23505"}, want: false},
+		{err: fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40001"}), want: true},
+	}
+
+	for _, tc := range tests {
+		if got := txn.Retryable(tc.err); got != tc.want {
+			t.Errorf("Retryable(%v): expected %t, got %t", tc.err, tc.want, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 5; attempt++ {
+		upper := 10 * time.Millisecond << (attempt - 1)
+		for i := 0; i < 100; i++ {
+			if got := txn.Backoff(attempt); got < upper/2 || got >= upper {
+				t.Fatalf("attempt %d: expected a backoff in [%s, %s), got %s", attempt, upper/2, upper, got)
+			}
+		}
+	}
+
+	// The doubling is capped, instead of overflowing into a negative duration
+	upper := 10 * time.Millisecond << 10
+	for _, attempt := range []int{11, 40, 64, 1000, math.MaxInt, 0, -1} {
+		if got := txn.Backoff(attempt); got <= 0 || got >= upper {
+			t.Errorf("attempt %d: expected a backoff in (0, %s), got %s", attempt, upper, got)
+		}
+	}
+}
+
+// noBackoff removes the wait between retries for the duration of the test
+func noBackoff(t *testing.T) {
+	backoff := txn.Backoff
+	txn.Backoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { txn.Backoff = backoff })
+}
+
+func TestWithTxRetry(t *testing.T) {
+	db := conformance.DB(t)
+	noBackoff(t)
+	ctx := context.Background()
+
+	t.Run("retryable", func(t *testing.T) {
+		var attempts int
+		err := txn.WithTx(ctx, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+			attempts++
+			if attempts < 3 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		var attempts int
+		err := txn.WithTx(ctx, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+			attempts++
+			return &pgconn.PgError{Code: "40P01"}
+		})
+		if !txn.Retryable(err) {
+			t.Errorf("expected the deadlock error, got: %v", err)
+		}
+		if attempts != txn.MaxAttempts {
+			t.Errorf("expected %d attempts, got %d", txn.MaxAttempts, attempts)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var attempts int
+		boom := errors.New("boom")
+		err := txn.WithTx(ctx, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+			attempts++
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Errorf("expected the boom error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+}
+
+func TestWithTxSavepoint(t *testing.T) {
+	db := conformance.DB(t)
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	err := txn.WithTx(ctx, db, nil, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "CREATE TEMPORARY TABLE savepoints (n INT) ON COMMIT DROP"); err != nil {
+			return err
+		}
+
+		// The failed inner transaction is rolled back to its savepoint
+		err := txn.WithTx(ctx, nil, nil, func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO savepoints VALUES (1)"); err != nil {
+				return err
+			}
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Errorf("expected the boom error, got: %v", err)
+		}
+
+		// Which leaves the outer transaction usable
+		err = txn.WithTx(ctx, nil, nil, func(ctx context.Context, tx *sql.Tx) error {
+			// Nest one level deeper
+			return txn.WithTx(ctx, nil, nil, func(ctx context.Context, tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO savepoints VALUES (2)")
+				return err
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		var sum int
+		if err = tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(n), 0) FROM savepoints").Scan(&sum); err != nil {
+			return err
+		}
+		if sum != 2 {
+			t.Errorf("expected only the second insert to remain, got a sum of %d", sum)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithPgxTxRetry(t *testing.T) {
+	db := conformance.Pool(t)
+	noBackoff(t)
+	ctx := context.Background()
+
+	var attempts int
+	err := txn.WithPgxTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		attempts++
+		if attempts < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWithPgxTxSavepoint(t *testing.T) {
+	db := conformance.Pool(t)
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	err := txn.WithPgxTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "CREATE TEMPORARY TABLE savepoints (n INT) ON COMMIT DROP"); err != nil {
+			return err
+		}
+
+		// The failed inner transaction is rolled back to its savepoint
+		err := txn.WithPgxTx(ctx, nil, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, "INSERT INTO savepoints VALUES (1)"); err != nil {
+				return err
+			}
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Errorf("expected the boom error, got: %v", err)
+		}
+
+		// Which leaves the outer transaction usable
+		err = txn.WithPgxTx(ctx, nil, pgx.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, "INSERT INTO savepoints VALUES (2)")
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		var sum int
+		if err = tx.QueryRow(ctx, "SELECT COALESCE(SUM(n), 0) FROM savepoints").Scan(&sum); err != nil {
+			return err
+		}
+		if sum != 2 {
+			t.Errorf("expected only the second insert to remain, got a sum of %d", sum)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}